@@ -3,6 +3,13 @@ package condition
 import (
 	"errors"
 	"fmt"
+	"github.com/hunknownz/godas/expression"
+	"github.com/hunknownz/godas/internal"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
 )
 
 const (
@@ -18,12 +25,101 @@ const (
 	ComparatorLTE = "<="
 	ComparatorIn = "in"
 	ComparatorIsNan = "is_nan"
+	ComparatorNotIn = "not in"
+	ComparatorNotNan = "not_nan"
+	ComparatorNotEq = "!="
+	ComparatorBetween = "between"
+	ComparatorContains = "contains"
+	ComparatorStartsWith = "startswith"
+	ComparatorEndsWith = "endswith"
+	ComparatorRegex = "regex"
+	ComparatorEqFold = "ieq"
+	ComparatorContainsFold = "icontains"
+	ComparatorStartsWithFold = "istartswith"
+	ComparatorEndsWithFold = "iendswith"
+	ComparatorRegexFold = "iregex"
+	ComparatorFunc = "func"
+	ComparatorRowFunc = "rowfunc"
 )
 
 type CompItem struct {
 	Comparator string
 	Column string
 	Value interface{}
+
+	pattern *regexp.Regexp
+	set map[interface{}]struct{}
+}
+
+func newCompItem(comparator string, column string, value interface{}) *CompItem {
+	item := &CompItem{
+		Comparator: comparator,
+		Column:     column,
+		Value:      value,
+	}
+	item.prepare()
+	return item
+}
+
+// SetValue replaces the value of the comparison and rebuilds the compiled
+// regex and membership set.
+func (item *CompItem) SetValue(value interface{}) {
+	item.Value = checkGetValue(value)
+	item.pattern = nil
+	item.set = nil
+	item.prepare()
+}
+
+// prepare compiles the regex and builds the membership set once, so they
+// aren't rebuilt for every element. Errors are reported on comparison.
+func (item *CompItem) prepare() {
+	switch item.Comparator {
+	case ComparatorRegex, ComparatorRegexFold:
+		if pattern, ok := item.Value.(string); ok {
+			item.compilePattern(pattern)
+		}
+	case ComparatorIn, ComparatorNotIn:
+		item.buildSet()
+	}
+}
+
+func (item *CompItem) buildSet() (set map[interface{}]struct{}, err error) {
+	if item.set != nil {
+		set = item.set
+		return
+	}
+	values, ok := item.Value.([]interface{})
+	if !ok {
+		err = errors.New(fmt.Sprintf("%s value %v must be a slice", item.Comparator, item.Value))
+		return
+	}
+	set = make(map[interface{}]struct{}, len(values))
+	for _, value := range values {
+		if value == nil || !reflect.TypeOf(value).Comparable() {
+			err = errors.New(fmt.Sprintf("%s value %v is not comparable", item.Comparator, value))
+			return
+		}
+		set[value] = struct{}{}
+	}
+	item.set = set
+	return
+}
+
+func (item *CompItem) compilePattern(pattern string) (re *regexp.Regexp, err error) {
+	if item.pattern != nil {
+		re = item.pattern
+		return
+	}
+	if item.Comparator == ComparatorRegexFold {
+		pattern = "(?i)" + pattern
+	}
+	re, err = regexp.Compile(pattern)
+	if err != nil {
+		err = fmt.Errorf("invalid regex %q: %w", pattern, err)
+		return
+	}
+	item.pattern = re
+	return
 }
 
 type CondValue struct {
@@ -32,54 +128,416 @@ type CondValue struct {
 	IsNot bool
 }
 
-func (condVal *CondValue) CompareInt(leftVal int64) (compareResult bool, err error) {
-	item := condVal.CompItem
-	rightVal, ok := item.Value.(int64)
-	if !ok {
-		err = errors.New(fmt.Sprintf("can't convert value %v to int", item.Value))
+// Range is the value of a ComparatorBetween comparison. Each bound is
+// inclusive or exclusive on its own.
+type Range struct {
+	Low, High interface{}
+	IncludeLow, IncludeHigh bool
+}
+
+// Between returns the inclusive range [low, high].
+func Between(low, high interface{}) Range {
+	return Range{
+		Low: low,
+		High: high,
+		IncludeLow: true,
+		IncludeHigh: true,
+	}
+}
+
+// ColumnRef is the value of a comparison against other columns of the same
+// dataframe, such as "budget * 0.9". Only the ordered comparators and = !=
+// are supported.
+type ColumnRef struct {
+	Expr expression.Node
+}
+
+func (ref ColumnRef) String() string {
+	return ref.Expr.String()
+}
+
+// Col references the column name as the value of a comparison.
+func Col(name string) ColumnRef {
+	return ColumnRef{
+		Expr: expression.ColumnNode{Name: name},
+	}
+}
+
+// ColExpr parses an arithmetic expression over columns, such as
+// "budget * 0.9", as the value of a comparison.
+func ColExpr(source string) (ref ColumnRef, err error) {
+	node, err := expression.Parse(source)
+	if err != nil {
 		return
 	}
-	switch item.Comparator {
+	ref = ColumnRef{
+		Expr: node,
+	}
+	return
+}
+
+// signFunc compares the left value of a comparison with right. It returns
+// the sign of left - right, and isNaN when one of them is a float NaN.
+type signFunc func(right interface{}) (sign int, isNaN bool, err error)
+
+func compareBySign(comparator string, sign int) (compareResult bool, ok bool) {
+	ok = true
+	switch comparator {
 	case ComparatorGT:
-		compareResult = leftVal > rightVal
+		compareResult = sign > 0
+	case ComparatorGTE:
+		compareResult = sign >= 0
 	case ComparatorEq:
-		compareResult = leftVal == rightVal
+		compareResult = sign == 0
+	case ComparatorNotEq:
+		compareResult = sign != 0
 	case ComparatorLT:
-		compareResult = leftVal < rightVal
-	case ComparatorGTE:
-		compareResult = leftVal >= rightVal
+		compareResult = sign < 0
 	case ComparatorLTE:
-		compareResult = leftVal <= rightVal
+		compareResult = sign <= 0
+	default:
+		ok = false
 	}
 	return
 }
 
-func (condVal *CondValue) CompareFloat64(leftVal float64) (compareResult bool, err error) {
+func (condVal *CondValue) compareOrdered(typ string, sign signFunc) (compareResult bool, err error) {
 	item := condVal.CompItem
-	rightVal, ok := item.Value.(float64)
+	if item.Comparator == ComparatorBetween {
+		r, ok := item.Value.(Range)
+		if !ok {
+			err = errors.New(fmt.Sprintf("between value %v must be a condition.Range", item.Value))
+			return
+		}
+		lowSign, lowNaN, e := sign(r.Low)
+		if e != nil {
+			err = e
+			return
+		}
+		highSign, highNaN, e := sign(r.High)
+		if e != nil {
+			err = e
+			return
+		}
+		if lowNaN || highNaN {
+			return
+		}
+		lowResult := lowSign > 0 || (r.IncludeLow && lowSign == 0)
+		highResult := highSign < 0 || (r.IncludeHigh && highSign == 0)
+		compareResult = lowResult && highResult
+		return
+	}
+
+	s, isNaN, err := sign(item.Value)
+	if err != nil {
+		return
+	}
+	compareResult, ok := compareBySign(item.Comparator, s)
 	if !ok {
-		err = errors.New(fmt.Sprintf("can't convert value %v to float64", item.Value))
+		err = errors.New(fmt.Sprintf("comparator %s is not supported for %s", item.Comparator, typ))
 		return
 	}
-	switch item.Comparator {
-	case ComparatorGT:
-		compareResult = leftVal > rightVal
-	case ComparatorLT:
-		compareResult = leftVal < rightVal
+	if isNaN {
+		compareResult = item.Comparator == ComparatorNotEq
+	}
+	return
+}
+
+func intSign(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func floatSign(a, b float64) (sign int, isNaN bool) {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b):
+		isNaN = true
+	case a < b:
+		sign = -1
+	case a > b:
+		sign = 1
+	}
+	return
+}
+
+func isMembership(comparator string) bool {
+	return comparator == ComparatorIn || comparator == ComparatorNotIn
+}
+
+// compareIn looks the keys up in the membership set of the in and not in
+// comparators. A numeric element passes both its int and float key so
+// that int and float lists match either column type.
+func (condVal *CondValue) compareIn(keys ...interface{}) (compareResult bool, err error) {
+	item := condVal.CompItem
+	set, err := item.buildSet()
+	if err != nil {
+		return
+	}
+	for _, key := range keys {
+		if _, ok := set[key]; ok {
+			compareResult = true
+			break
+		}
+	}
+	if item.Comparator == ComparatorNotIn {
+		compareResult = !compareResult
+	}
+	return
+}
+
+// CompareNaN evaluates the is_nan and not_nan comparators.
+func (condVal *CondValue) CompareNaN(isNaN bool) (compareResult bool, err error) {
+	switch condVal.CompItem.Comparator {
+	case ComparatorIsNan:
+		compareResult = isNaN
+	case ComparatorNotNan:
+		compareResult = !isNaN
+	default:
+		err = errors.New(fmt.Sprintf("comparator %s is not a nan comparator", condVal.CompItem.Comparator))
 	}
 	return
 }
 
+func IsNaNComparator(comparator string) bool {
+	return comparator == ComparatorIsNan || comparator == ComparatorNotNan
+}
+
+func (condVal *CondValue) CompareInt(leftVal int64) (compareResult bool, err error) {
+	if isMembership(condVal.CompItem.Comparator) {
+		return condVal.compareIn(leftVal, float64(leftVal))
+	}
+	return condVal.compareOrdered("int", func(right interface{}) (sign int, isNaN bool, err error) {
+		switch right.(type) {
+		case int64:
+			sign = intSign(leftVal, right.(int64))
+		case float64:
+			sign, isNaN = floatSign(float64(leftVal), right.(float64))
+		default:
+			err = errors.New(fmt.Sprintf("can't convert value %v to int", right))
+		}
+		return
+	})
+}
+
+func (condVal *CondValue) CompareFloat64(leftVal float64) (compareResult bool, err error) {
+	if isMembership(condVal.CompItem.Comparator) {
+		if leftVal == math.Trunc(leftVal) && math.Abs(leftVal) < math.MaxInt64 {
+			return condVal.compareIn(leftVal, int64(leftVal))
+		}
+		return condVal.compareIn(leftVal)
+	}
+	return condVal.compareOrdered("float", func(right interface{}) (sign int, isNaN bool, err error) {
+		switch right.(type) {
+		case int64:
+			sign, isNaN = floatSign(leftVal, float64(right.(int64)))
+		case float64:
+			sign, isNaN = floatSign(leftVal, right.(float64))
+		default:
+			err = errors.New(fmt.Sprintf("can't convert value %v to float64", right))
+		}
+		return
+	})
+}
+
 func (condVal *CondValue) CompareBool(leftVal bool) (compareResult bool, err error) {
+	if isMembership(condVal.CompItem.Comparator) {
+		return condVal.compareIn(leftVal)
+	}
+	return condVal.compareOrdered("bool", func(right interface{}) (sign int, isNaN bool, err error) {
+		rightVal, ok := right.(bool)
+		if !ok {
+			err = errors.New(fmt.Sprintf("can't convert value %v to bool", right))
+			return
+		}
+		switch {
+		case !leftVal && rightVal:
+			sign = -1
+		case leftVal && !rightVal:
+			sign = 1
+		}
+		return
+	})
+}
+
+func (condVal *CondValue) CompareString(leftVal string) (compareResult bool, err error) {
 	item := condVal.CompItem
-	rightVal, ok := item.Value.(bool)
+	switch item.Comparator {
+	case ComparatorIn, ComparatorNotIn:
+		return condVal.compareIn(leftVal)
+	case ComparatorGT, ComparatorGTE, ComparatorEq, ComparatorNotEq, ComparatorLT, ComparatorLTE, ComparatorBetween:
+		return condVal.compareOrdered("string", func(right interface{}) (sign int, isNaN bool, err error) {
+			rightVal, ok := right.(string)
+			if !ok {
+				err = errors.New(fmt.Sprintf("can't convert value %v to string", right))
+				return
+			}
+			sign = strings.Compare(leftVal, rightVal)
+			return
+		})
+	}
+
+	rightVal, ok := item.Value.(string)
 	if !ok {
-		err = errors.New(fmt.Sprintf("can't convert value %v to bool", item.Value))
+		err = errors.New(fmt.Sprintf("can't convert value %v to string", item.Value))
+		return
+	}
+	switch item.Comparator {
+	case ComparatorContains:
+		compareResult = strings.Contains(leftVal, rightVal)
+	case ComparatorStartsWith:
+		compareResult = strings.HasPrefix(leftVal, rightVal)
+	case ComparatorEndsWith:
+		compareResult = strings.HasSuffix(leftVal, rightVal)
+	case ComparatorEqFold:
+		compareResult = strings.EqualFold(leftVal, rightVal)
+	case ComparatorContainsFold:
+		compareResult = strings.Contains(strings.ToLower(leftVal), strings.ToLower(rightVal))
+	case ComparatorStartsWithFold:
+		compareResult = len(leftVal) >= len(rightVal) && strings.EqualFold(leftVal[:len(rightVal)], rightVal)
+	case ComparatorEndsWithFold:
+		compareResult = len(leftVal) >= len(rightVal) && strings.EqualFold(leftVal[len(leftVal)-len(rightVal):], rightVal)
+	case ComparatorRegex, ComparatorRegexFold:
+		re, e := item.compilePattern(rightVal)
+		if e != nil {
+			err = e
+			return
+		}
+		compareResult = re.MatchString(leftVal)
+	default:
+		err = errors.New(fmt.Sprintf("comparator %s is not supported for string", item.Comparator))
+	}
+	return
+}
+
+// toTime converts a time.Time or an RFC 3339 string to a time.
+func toTime(value interface{}) (t time.Time, err error) {
+	switch value.(type) {
+	case time.Time:
+		t = value.(time.Time)
+		return
+	case string:
+		if t, err = time.Parse(time.RFC3339Nano, value.(string)); err == nil {
+			return
+		}
+	}
+	err = errors.New(fmt.Sprintf("can't convert value %v to time", value))
+	return
+}
+
+// CompareTime compares the datetime leftVal with time.Time values or RFC
+// 3339 strings, times being equal when they denote the same instant
+// whatever their location.
+func (condVal *CondValue) CompareTime(leftVal time.Time) (compareResult bool, err error) {
+	item := condVal.CompItem
+	if isMembership(item.Comparator) {
+		values, ok := item.Value.([]interface{})
+		if !ok {
+			err = errors.New(fmt.Sprintf("%s value %v must be a slice", item.Comparator, item.Value))
+			return
+		}
+		for _, value := range values {
+			rightVal, e := toTime(value)
+			if e != nil {
+				err = e
+				return
+			}
+			if leftVal.Equal(rightVal) {
+				compareResult = true
+				break
+			}
+		}
+		if item.Comparator == ComparatorNotIn {
+			compareResult = !compareResult
+		}
+		return
+	}
+	return condVal.compareOrdered("datetime", func(right interface{}) (sign int, isNaN bool, err error) {
+		rightVal, err := toTime(right)
+		if err != nil {
+			return
+		}
+		switch {
+		case leftVal.Before(rightVal):
+			sign = -1
+		case leftVal.After(rightVal):
+			sign = 1
+		}
+		return
+	})
+}
+
+// toDuration converts a time.Duration or a duration string, Go or ISO
+// 8601, to a duration.
+func toDuration(value interface{}) (d time.Duration, err error) {
+	switch value.(type) {
+	case time.Duration:
+		d = value.(time.Duration)
+		return
+	case string:
+		if d, err = internal.ParseDuration(value.(string)); err == nil {
+			return
+		}
+	}
+	err = errors.New(fmt.Sprintf("can't convert value %v to duration", value))
+	return
+}
+
+// CompareDuration compares the duration leftVal with time.Duration values
+// or duration strings.
+func (condVal *CondValue) CompareDuration(leftVal time.Duration) (compareResult bool, err error) {
+	item := condVal.CompItem
+	if isMembership(item.Comparator) {
+		values, ok := item.Value.([]interface{})
+		if !ok {
+			err = errors.New(fmt.Sprintf("%s value %v must be a slice", item.Comparator, item.Value))
+			return
+		}
+		for _, value := range values {
+			rightVal, e := toDuration(value)
+			if e != nil {
+				err = e
+				return
+			}
+			if leftVal == rightVal {
+				compareResult = true
+				break
+			}
+		}
+		if item.Comparator == ComparatorNotIn {
+			compareResult = !compareResult
+		}
 		return
 	}
+	return condVal.compareOrdered("duration", func(right interface{}) (sign int, isNaN bool, err error) {
+		rightVal, err := toDuration(right)
+		if err != nil {
+			return
+		}
+		sign = intSign(int64(leftVal), int64(rightVal))
+		return
+	})
+}
+
+func (condVal *CondValue) CompareObject(leftVal interface{}) (compareResult bool, err error) {
+	item := condVal.CompItem
 	switch item.Comparator {
+	case ComparatorIn, ComparatorNotIn:
+		if leftVal != nil && !reflect.TypeOf(leftVal).Comparable() {
+			compareResult = item.Comparator == ComparatorNotIn
+			return
+		}
+		return condVal.compareIn(leftVal)
 	case ComparatorEq:
-		compareResult = leftVal == rightVal
+		compareResult = reflect.DeepEqual(leftVal, item.Value)
+	case ComparatorNotEq:
+		compareResult = !reflect.DeepEqual(leftVal, item.Value)
+	default:
+		err = errors.New(fmt.Sprintf("comparator %s is not supported for object", item.Comparator))
 	}
 	return
 }
@@ -96,8 +554,11 @@ func (condVal *CondValue) String() string {
 		condString = fmt.Sprintf("(%s %s %v)",
 			comp.Column, comp.Comparator, comp.Value)
 	}
-	if condVal.Cond != nil {
-		condString = fmt.Sprintf("%s", condVal.Cond)
+	if condVal.Cond != nil && condVal.IsNot {
+		condString = fmt.Sprintf("!(%s)", condVal.Cond)
+	}
+	if condVal.Cond != nil && !condVal.IsNot {
+		condString = fmt.Sprintf("(%s)", condVal.Cond)
 	}
 	return condString
 }
@@ -105,6 +566,7 @@ func (condVal *CondValue) String() string {
 type Condition struct {
 	ast *condAST
 	condType int
+	missing int
 }
 
 func (cond *Condition) String() string {
@@ -128,35 +590,36 @@ func checkGetValue(val interface{}) (value interface{}) {
 		value = int64(val.(int32))
 	case float32:
 		value = float64(val.(float32))
+	case Range:
+		r := val.(Range)
+		r.Low = checkGetValue(r.Low)
+		r.High = checkGetValue(r.High)
+		value = r
 	default:
-		value = val
+		rv := reflect.ValueOf(val)
+		if rv.Kind() != reflect.Slice {
+			value = val
+			break
+		}
+		values := make([]interface{}, rv.Len())
+		for i := range values {
+			values[i] = checkGetValue(rv.Index(i).Interface())
+		}
+		value = values
 	}
 	return
 }
 
-func (cond *Condition) And(comparator string, value interface{}, columns ...string) *Condition {
-	value = checkGetValue(value)
+// appendCondValue appends condVal to the condition, joined to the previous
+// tokens by the operator of operatorType.
+func (cond *Condition) appendCondValue(operatorType int, condVal *CondValue) *Condition {
 	ast := cond.ast
-	var column string
-	if len(columns) > 0 {
-		column = columns[0]
-	}
 	if ast.curIndex != -1 {
 		tokenOperator := &condToken{
-			tokenType: tokenOperatorAnd,
+			tokenType: operatorType,
 		}
 		ast.tokens = append(ast.tokens, tokenOperator)
 	}
-	cmp := &CompItem{
-		Comparator: comparator,
-		Column:     column,
-		Value:      value,
-	}
-	condVal := &CondValue{
-		Cond: nil,
-		CompItem: cmp,
-		IsNot: false,
-	}
 	tokenLiteral := &condToken{
 		cond:      condVal,
 		tokenType: tokenLiteral,
@@ -167,107 +630,112 @@ func (cond *Condition) And(comparator string, value interface{}, columns ...stri
 		ast.curIndex = 0
 		ast.curToken = ast.tokens[ast.curIndex]
 	}
-
+	ast.expr = nil
 	return cond
 }
 
-func (cond *Condition) Or(comparator string, value interface{}, columns ...string) *Condition {
+func newCompCondValue(comparator string, value interface{}, columns []string, isNot bool) *CondValue {
 	value = checkGetValue(value)
-	ast := cond.ast
 	var column string
 	if len(columns) > 0 {
 		column = columns[0]
 	}
-
-	if ast.curIndex != -1 {
-		tokenOperator := &condToken{
-			tokenType: tokenOperatorOr,
-		}
-		ast.tokens = append(ast.tokens, tokenOperator)
-	}
-	cmp := &CompItem{
-		Comparator: comparator,
-		Column:     column,
-		Value:      value,
-	}
-	condVal := &CondValue{
+	cmp := newCompItem(comparator, column, value)
+	return &CondValue{
 		Cond: nil,
 		CompItem: cmp,
-		IsNot: false,
-	}
-	tokenLiteral := &condToken{
-		cond:      condVal,
-		tokenType: tokenLiteral,
+		IsNot: isNot,
 	}
-	ast.tokens = append(ast.tokens, tokenLiteral)
+}
 
-	if ast.curIndex == -1 {
-		ast.curIndex = 0
-		ast.curToken = ast.tokens[ast.curIndex]
-	}
+func (cond *Condition) And(comparator string, value interface{}, columns ...string) *Condition {
+	condVal := newCompCondValue(comparator, value, columns, false)
+	return cond.appendCondValue(tokenOperatorAnd, condVal)
+}
 
-	return cond
+func (cond *Condition) Or(comparator string, value interface{}, columns ...string) *Condition {
+	condVal := newCompCondValue(comparator, value, columns, false)
+	return cond.appendCondValue(tokenOperatorOr, condVal)
 }
 
-func (cond *Condition) AndCond(otherCond *Condition) *Condition {
-	ast := cond.ast
-	if ast.curIndex != -1 {
-		tokenOperator := &condToken{
-			tokenType: tokenOperatorAnd,
-		}
-		ast.tokens = append(ast.tokens, tokenOperator)
-	}
+// AndNot appends "&& !(column comparator value)".
+func (cond *Condition) AndNot(comparator string, value interface{}, columns ...string) *Condition {
+	condVal := newCompCondValue(comparator, value, columns, true)
+	return cond.appendCondValue(tokenOperatorAnd, condVal)
+}
+
+// OrNot appends "|| !(column comparator value)".
+func (cond *Condition) OrNot(comparator string, value interface{}, columns ...string) *Condition {
+	condVal := newCompCondValue(comparator, value, columns, true)
+	return cond.appendCondValue(tokenOperatorOr, condVal)
+}
 
+func (cond *Condition) AndCond(otherCond *Condition) *Condition {
 	condVal := &CondValue{
 		Cond: otherCond,
 		CompItem: nil,
 		IsNot: false,
 	}
-	tokenLiteral := &condToken{
-		cond:      condVal,
-		tokenType: tokenLiteral,
-	}
-	ast.tokens = append(ast.tokens, tokenLiteral)
-
-	if ast.curIndex == -1 {
-		ast.curIndex = 0
-		ast.curToken = ast.tokens[ast.curIndex]
-	}
-	return cond
+	return cond.appendCondValue(tokenOperatorAnd, condVal)
 }
 
 func (cond *Condition) OrCond(otherCond *Condition) *Condition {
-	ast := cond.ast
-	if ast.curIndex != -1 {
-		tokenOperator := &condToken{
-			tokenType: tokenOperatorOr,
-		}
-		ast.tokens = append(ast.tokens, tokenOperator)
+	condVal := &CondValue{
+		Cond: otherCond,
+		CompItem: nil,
+		IsNot: false,
 	}
+	return cond.appendCondValue(tokenOperatorOr, condVal)
+}
 
+func (cond *Condition) AndNotCond(otherCond *Condition) *Condition {
 	condVal := &CondValue{
 		Cond: otherCond,
 		CompItem: nil,
-		IsNot: false,
+		IsNot: true,
 	}
-	tokenLiteral := &condToken{
-		cond:      condVal,
-		tokenType: tokenLiteral,
+	return cond.appendCondValue(tokenOperatorAnd, condVal)
+}
+
+func (cond *Condition) OrNotCond(otherCond *Condition) *Condition {
+	condVal := &CondValue{
+		Cond: otherCond,
+		CompItem: nil,
+		IsNot: true,
 	}
-	ast.tokens = append(ast.tokens, tokenLiteral)
+	return cond.appendCondValue(tokenOperatorOr, condVal)
+}
 
-	if ast.curIndex == -1 {
-		ast.curIndex = 0
-		ast.curToken = ast.tokens[ast.curIndex]
+// Not negates everything built so far, so that
+// cond.And(">", 1, "a").And("<", 5, "b").Not() reads "!(a > 1 && b < 5)".
+func (cond *Condition) Not() *Condition {
+	inner := &Condition{
+		ast: cond.ast,
+		condType: cond.condType,
+	}
+	cond.ast = NewAST()
+	condVal := &CondValue{
+		Cond: inner,
+		CompItem: nil,
+		IsNot: true,
 	}
-	return cond
+	return cond.appendCondValue(tokenOperatorAnd, condVal)
+}
+
+// NotCond returns a new condition which is the negation of otherCond.
+func NotCond(otherCond *Condition) *Condition {
+	cond := NewCondition(otherCond.condType)
+	return cond.AndNotCond(otherCond)
 }
 
 func (cond *Condition) Prepare() ExprAST {
-	if cond.ast.expr == nil {
-		cond.ast.expr = cond.ast.parseExpr()
+	ast := cond.ast
+	if ast.expr == nil && len(ast.tokens) > 0 {
+		ast.curIndex = 0
+		ast.curToken = ast.tokens[ast.curIndex]
+		ast.expr = ast.parseExpr()
 	}
-	return cond.ast.expr
+	return ast.expr
 }
 
 func NewCondition(condType int) *Condition {
@@ -0,0 +1,189 @@
+package condition
+
+import "testing"
+
+func newCompValue(comparator string, value interface{}) *CondValue {
+	return &CondValue{
+		CompItem: &CompItem{
+			Comparator: comparator,
+			Value:      checkGetValue(value),
+		},
+	}
+}
+
+func TestCompareString(t *testing.T) {
+	cases := []struct {
+		comparator string
+		value      string
+		left       string
+		want       bool
+	}{
+		{ComparatorEq, "DE", "DE", true},
+		{ComparatorNotEq, "DE", "FR", true},
+		{ComparatorLT, "b", "a", true},
+		{ComparatorGTE, "b", "a", false},
+		{ComparatorContains, "ell", "hello", true},
+		{ComparatorStartsWith, "he", "hello", true},
+		{ComparatorEndsWith, "lo", "hello", true},
+		{ComparatorRegex, "^h.*o$", "hello", true},
+		{ComparatorRegex, "^H", "hello", false},
+		{ComparatorEqFold, "HELLO", "hello", true},
+		{ComparatorContainsFold, "ELL", "hello", true},
+		{ComparatorStartsWithFold, "HE", "hello", true},
+		{ComparatorEndsWithFold, "LO", "hello", true},
+		{ComparatorEndsWithFold, "LONGER", "lo", false},
+		{ComparatorRegexFold, "^H", "hello", true},
+	}
+	for _, c := range cases {
+		got, err := newCompValue(c.comparator, c.value).CompareString(c.left)
+		if err != nil {
+			t.Errorf("%s: %v", c.comparator, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%q %s %q: got %v, want %v", c.left, c.comparator, c.value, got, c.want)
+		}
+	}
+
+	if _, err := newCompValue(ComparatorRegex, "(").CompareString("a"); err == nil {
+		t.Error("invalid regex should fail")
+	}
+	if _, err := newCompValue(ComparatorEq, 1).CompareString("a"); err == nil {
+		t.Error("int value should fail")
+	}
+}
+
+func TestCompareNumbers(t *testing.T) {
+	comparators := []string{ComparatorEq, ComparatorNotEq, ComparatorLT, ComparatorLTE, ComparatorGT, ComparatorGTE}
+	expected := map[string][3]bool{
+		ComparatorEq:    {false, true, false},
+		ComparatorNotEq: {true, false, true},
+		ComparatorLT:    {true, false, false},
+		ComparatorLTE:   {true, true, false},
+		ComparatorGT:    {false, false, true},
+		ComparatorGTE:   {false, true, true},
+	}
+	for _, comparator := range comparators {
+		for i, left := range []int64{1, 2, 3} {
+			want := expected[comparator][i]
+			got, err := newCompValue(comparator, 2).CompareInt(left)
+			if err != nil || got != want {
+				t.Errorf("int %d %s 2: got %v %v, want %v", left, comparator, got, err, want)
+			}
+			got, err = newCompValue(comparator, 2).CompareFloat64(float64(left))
+			if err != nil || got != want {
+				t.Errorf("float %d %s 2: got %v %v, want %v", left, comparator, got, err, want)
+			}
+			got, err = newCompValue(comparator, 2.0).CompareInt(left)
+			if err != nil || got != want {
+				t.Errorf("int %d %s 2.0: got %v %v, want %v", left, comparator, got, err, want)
+			}
+		}
+	}
+
+	if _, err := newCompValue("~", 2).CompareInt(1); err == nil {
+		t.Error("unknown comparator should fail for int")
+	}
+	if _, err := newCompValue("~", 2.0).CompareFloat64(1); err == nil {
+		t.Error("unknown comparator should fail for float")
+	}
+	if _, err := newCompValue(ComparatorEq, "2").CompareInt(1); err == nil {
+		t.Error("string value should fail for int")
+	}
+}
+
+func TestCompareBool(t *testing.T) {
+	got, _ := newCompValue(ComparatorNotEq, true).CompareBool(false)
+	if !got {
+		t.Error("false != true should be true")
+	}
+	got, _ = newCompValue(ComparatorLT, true).CompareBool(false)
+	if !got {
+		t.Error("false < true should be true")
+	}
+}
+
+func TestCompareBetween(t *testing.T) {
+	inclusive := newCompValue(ComparatorBetween, Between(1, 3))
+	exclusive := newCompValue(ComparatorBetween, Range{Low: 1, High: 3.5})
+	cases := []struct {
+		left      float64
+		inclusive bool
+		exclusive bool
+	}{
+		{0.5, false, false},
+		{1, true, false},
+		{3, true, true},
+		{3.5, false, false},
+	}
+	for _, c := range cases {
+		got, err := inclusive.CompareFloat64(c.left)
+		if err != nil || got != c.inclusive {
+			t.Errorf("%v in [1, 3]: got %v %v", c.left, got, err)
+		}
+		got, err = exclusive.CompareFloat64(c.left)
+		if err != nil || got != c.exclusive {
+			t.Errorf("%v in (1, 3.5): got %v %v", c.left, got, err)
+		}
+	}
+	got, _ := newCompValue(ComparatorBetween, Between("b", "d")).CompareString("c")
+	if !got {
+		t.Error("c should be between b and d")
+	}
+	if _, err := newCompValue(ComparatorBetween, 1).CompareInt(1); err == nil {
+		t.Error("between without range should fail")
+	}
+}
+
+func TestCompareIn(t *testing.T) {
+	ids := make([]int, 5000)
+	for i := range ids {
+		ids[i] = i * 2
+	}
+	in := newCompValue(ComparatorIn, ids)
+	in.CompItem.prepare()
+	got, err := in.CompareInt(4000)
+	if err != nil || !got {
+		t.Errorf("4000 in ids: got %v %v", got, err)
+	}
+	got, _ = in.CompareInt(4001)
+	if got {
+		t.Error("4001 should not be in ids")
+	}
+	got, _ = in.CompareFloat64(4000)
+	if !got {
+		t.Error("float 4000 should be in int ids")
+	}
+
+	notIn := newCompValue(ComparatorNotIn, []string{"DE", "FR"})
+	got, _ = notIn.CompareString("IT")
+	if !got {
+		t.Error("IT should not be in DE, FR")
+	}
+	got, _ = newCompValue(ComparatorIn, []bool{true}).CompareBool(false)
+	if got {
+		t.Error("false should not be in [true]")
+	}
+	if _, err := newCompValue(ComparatorIn, 1).CompareInt(1); err == nil {
+		t.Error("in without slice should fail")
+	}
+}
+
+func TestConditionNot(t *testing.T) {
+	cond := NewCondition(ConditionTypeDataFrame)
+	cond.And(ComparatorGT, 1, "a").OrNot(ComparatorEq, "x", "b")
+	if got, want := cond.String(), "(a > 1)||!(b = x)"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	cond.Not()
+	if got, want := cond.String(), "!((a > 1)||!(b = x))"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	other := NewCondition(ConditionTypeDataFrame)
+	other.And(ComparatorLT, 5, "c")
+	if got, want := NotCond(other).String(), "!((c < 5))"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,497 @@
+package condition
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/expression"
+	"math"
+	"strconv"
+	"time"
+)
+
+// The JSON schema of a condition is
+//
+//	{"type": "dataframe", "terms": [term, ...]}
+//
+// where every term is joined to the previous one by "op" ("&&" or "||",
+// omitted for the first term), may be negated by "not", and holds either a
+// nested condition in "cond" or a "column", "comparator" and typed "value".
+// Values are {"type": t, "value": v} with t one of null, int, float,
+// string, bool, datetime, duration, list, range and expr. Ints are encoded
+// as JSON integers and floats keep their type, so int64 and float64
+// literals round-trip exactly. NaN and infinite floats are encoded as the
+// strings "NaN", "+Inf" and "-Inf". Datetimes are RFC 3339 strings with
+// nanoseconds and the offset of their location, named in "location" when
+// it is a time zone database location such as "America/New_York".
+// Durations are JSON integers of nanoseconds. A missing policy other than
+// MissingUnknown is stored in "missing" as "false" or "true".
+
+const (
+	jsonTypeSeries    = "series"
+	jsonTypeDataFrame = "dataframe"
+
+	jsonValueNull     = "null"
+	jsonValueInt      = "int"
+	jsonValueFloat    = "float"
+	jsonValueString   = "string"
+	jsonValueBool     = "bool"
+	jsonValueDatetime = "datetime"
+	jsonValueDuration = "duration"
+	jsonValueList     = "list"
+	jsonValueRange    = "range"
+	jsonValueExpr     = "expr"
+
+	jsonNodeNumber = "number"
+	jsonNodeString = "string"
+	jsonNodeBool   = "bool"
+	jsonNodeColumn = "column"
+	jsonNodeUnary  = "unary"
+	jsonNodeBinary = "binary"
+	jsonNodeCall   = "call"
+
+	jsonMissingFalse = "false"
+	jsonMissingTrue  = "true"
+)
+
+type jsonCondition struct {
+	Type    string     `json:"type"`
+	Missing string     `json:"missing,omitempty"`
+	Terms   []jsonTerm `json:"terms"`
+}
+
+type jsonTerm struct {
+	Op         string     `json:"op,omitempty"`
+	Not        bool       `json:"not,omitempty"`
+	Cond       *Condition `json:"cond,omitempty"`
+	Column     string     `json:"column,omitempty"`
+	Comparator string     `json:"comparator,omitempty"`
+	Value      *jsonValue `json:"value,omitempty"`
+}
+
+type jsonValue struct {
+	Type     string          `json:"type"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Location string          `json:"location,omitempty"`
+}
+
+type jsonRange struct {
+	Low         *jsonValue `json:"low"`
+	High        *jsonValue `json:"high"`
+	IncludeLow  bool       `json:"include_low"`
+	IncludeHigh bool       `json:"include_high"`
+}
+
+type jsonNode struct {
+	Node    string      `json:"node"`
+	Value   *jsonValue  `json:"value,omitempty"`
+	Name    string      `json:"name,omitempty"`
+	Op      string      `json:"op,omitempty"`
+	Operand *jsonNode   `json:"operand,omitempty"`
+	Lhs     *jsonNode   `json:"lhs,omitempty"`
+	Rhs     *jsonNode   `json:"rhs,omitempty"`
+	Args    []*jsonNode `json:"args,omitempty"`
+}
+
+func (cond *Condition) MarshalJSON() ([]byte, error) {
+	jsonCond := jsonCondition{
+		Type:  jsonTypeSeries,
+		Terms: make([]jsonTerm, 0),
+	}
+	if cond.condType == ConditionTypeDataFrame {
+		jsonCond.Type = jsonTypeDataFrame
+	}
+	switch cond.missing {
+	case MissingFalse:
+		jsonCond.Missing = jsonMissingFalse
+	case MissingTrue:
+		jsonCond.Missing = jsonMissingTrue
+	}
+
+	var op string
+	for _, token := range cond.ast.tokens {
+		switch token.tokenType {
+		case tokenOperatorAnd:
+			op = operatorAnd
+			continue
+		case tokenOperatorOr:
+			op = operatorOr
+			continue
+		}
+		condVal := token.cond
+		term := jsonTerm{
+			Op:  op,
+			Not: condVal.IsNot,
+		}
+		if condVal.Cond != nil {
+			term.Cond = condVal.Cond
+		} else {
+			value, err := marshalValue(condVal.CompItem.Value)
+			if err != nil {
+				return nil, fmt.Errorf("marshal condition error: %w", err)
+			}
+			term.Column = condVal.CompItem.Column
+			term.Comparator = condVal.CompItem.Comparator
+			term.Value = value
+		}
+		jsonCond.Terms = append(jsonCond.Terms, term)
+	}
+	return json.Marshal(jsonCond)
+}
+
+func (cond *Condition) UnmarshalJSON(data []byte) (err error) {
+	var jsonCond jsonCondition
+	err = json.Unmarshal(data, &jsonCond)
+	if err != nil {
+		return fmt.Errorf("unmarshal condition error: %w", err)
+	}
+
+	var newCond *Condition
+	switch jsonCond.Type {
+	case jsonTypeSeries:
+		newCond = NewCondition(ConditionTypeSeries)
+	case jsonTypeDataFrame:
+		newCond = NewCondition(ConditionTypeDataFrame)
+	default:
+		return errors.New(fmt.Sprintf("unmarshal condition error: unknown condition type %q", jsonCond.Type))
+	}
+	switch jsonCond.Missing {
+	case "":
+	case jsonMissingFalse:
+		newCond.missing = MissingFalse
+	case jsonMissingTrue:
+		newCond.missing = MissingTrue
+	default:
+		return errors.New(fmt.Sprintf("unmarshal condition error: unknown missing policy %q", jsonCond.Missing))
+	}
+
+	for i, term := range jsonCond.Terms {
+		operatorType := tokenOperatorAnd
+		switch {
+		case i == 0 && term.Op == "":
+		case i > 0 && term.Op == operatorAnd:
+		case i > 0 && term.Op == operatorOr:
+			operatorType = tokenOperatorOr
+		default:
+			return errors.New(fmt.Sprintf("unmarshal condition error: invalid operator %q in term %d", term.Op, i))
+		}
+
+		condVal := &CondValue{
+			IsNot: term.Not,
+		}
+		if term.Cond != nil {
+			condVal.Cond = term.Cond
+		} else {
+			if term.Comparator == "" {
+				return errors.New(fmt.Sprintf("unmarshal condition error: term %d has no comparator", i))
+			}
+			value, e := unmarshalValue(term.Value)
+			if e != nil {
+				return fmt.Errorf("unmarshal condition error: term %d: %w", i, e)
+			}
+			condVal.CompItem = newCompItem(term.Comparator, term.Column, value)
+		}
+		newCond.appendCondValue(operatorType, condVal)
+	}
+	*cond = *newCond
+	return
+}
+
+func marshalValue(value interface{}) (jsonVal *jsonValue, err error) {
+	var raw interface{}
+	jsonVal = new(jsonValue)
+	switch value.(type) {
+	case nil:
+		jsonVal.Type = jsonValueNull
+		return
+	case int64:
+		jsonVal.Type = jsonValueInt
+		raw = value
+	case float64:
+		jsonVal.Type = jsonValueFloat
+		raw = value
+		floatValue := value.(float64)
+		if math.IsNaN(floatValue) || math.IsInf(floatValue, 0) {
+			raw = strconv.FormatFloat(floatValue, 'g', -1, 64)
+		}
+	case string:
+		jsonVal.Type = jsonValueString
+		raw = value
+	case bool:
+		jsonVal.Type = jsonValueBool
+		raw = value
+	case time.Time:
+		t := value.(time.Time)
+		jsonVal.Type = jsonValueDatetime
+		jsonVal.Location = locationName(t)
+		raw = t.Format(time.RFC3339Nano)
+	case time.Duration:
+		jsonVal.Type = jsonValueDuration
+		raw = int64(value.(time.Duration))
+	case []interface{}:
+		values := value.([]interface{})
+		list := make([]*jsonValue, len(values))
+		for i, v := range values {
+			list[i], err = marshalValue(v)
+			if err != nil {
+				return
+			}
+		}
+		jsonVal.Type = jsonValueList
+		raw = list
+	case Range:
+		r := value.(Range)
+		jsonR := jsonRange{
+			IncludeLow:  r.IncludeLow,
+			IncludeHigh: r.IncludeHigh,
+		}
+		jsonR.Low, err = marshalValue(r.Low)
+		if err != nil {
+			return
+		}
+		jsonR.High, err = marshalValue(r.High)
+		if err != nil {
+			return
+		}
+		jsonVal.Type = jsonValueRange
+		raw = jsonR
+	case ColumnRef:
+		node, e := marshalNode(value.(ColumnRef).Expr)
+		if e != nil {
+			err = e
+			return
+		}
+		jsonVal.Type = jsonValueExpr
+		raw = node
+	default:
+		err = errors.New(fmt.Sprintf("value %v of type %T can't be marshaled", value, value))
+		return
+	}
+	jsonVal.Value, err = json.Marshal(raw)
+	return
+}
+
+func unmarshalValue(jsonVal *jsonValue) (value interface{}, err error) {
+	if jsonVal == nil {
+		err = errors.New("missing value")
+		return
+	}
+	raw := jsonVal.Value
+	switch jsonVal.Type {
+	case jsonValueNull:
+	case jsonValueInt:
+		value, err = strconv.ParseInt(string(raw), 10, 64)
+	case jsonValueFloat:
+		text := string(raw)
+		if len(raw) > 0 && raw[0] == '"' {
+			err = json.Unmarshal(raw, &text)
+			if err != nil {
+				return
+			}
+		}
+		value, err = strconv.ParseFloat(text, 64)
+	case jsonValueString:
+		var stringValue string
+		err = json.Unmarshal(raw, &stringValue)
+		value = stringValue
+	case jsonValueBool:
+		var boolValue bool
+		err = json.Unmarshal(raw, &boolValue)
+		value = boolValue
+	case jsonValueDatetime:
+		var timeValue string
+		err = json.Unmarshal(raw, &timeValue)
+		if err != nil {
+			return
+		}
+		t, e := time.Parse(time.RFC3339Nano, timeValue)
+		if e != nil || jsonVal.Location == "" {
+			value, err = t, e
+			return
+		}
+		location, e := time.LoadLocation(jsonVal.Location)
+		if e != nil {
+			err = fmt.Errorf("datetime location error: %w", e)
+			return
+		}
+		value = t.In(location)
+	case jsonValueDuration:
+		nanos, e := strconv.ParseInt(string(raw), 10, 64)
+		value, err = time.Duration(nanos), e
+	case jsonValueList:
+		var list []*jsonValue
+		err = json.Unmarshal(raw, &list)
+		if err != nil {
+			return
+		}
+		values := make([]interface{}, len(list))
+		for i, v := range list {
+			values[i], err = unmarshalValue(v)
+			if err != nil {
+				return
+			}
+		}
+		value = values
+	case jsonValueRange:
+		var jsonR jsonRange
+		err = json.Unmarshal(raw, &jsonR)
+		if err != nil {
+			return
+		}
+		r := Range{
+			IncludeLow:  jsonR.IncludeLow,
+			IncludeHigh: jsonR.IncludeHigh,
+		}
+		r.Low, err = unmarshalValue(jsonR.Low)
+		if err != nil {
+			return
+		}
+		r.High, err = unmarshalValue(jsonR.High)
+		value = r
+	case jsonValueExpr:
+		var node jsonNode
+		err = json.Unmarshal(raw, &node)
+		if err != nil {
+			return
+		}
+		expr, e := unmarshalNode(&node)
+		if e != nil {
+			err = e
+			return
+		}
+		value = ColumnRef{
+			Expr: expr,
+		}
+	default:
+		err = errors.New(fmt.Sprintf("unknown value type %q", jsonVal.Type))
+	}
+	return
+}
+
+// locationName returns the name of the location of t when loading it gives
+// back the same offset at t, and "" for UTC and fixed offsets, which the
+// RFC 3339 offset alone restores.
+func locationName(t time.Time) (name string) {
+	name = t.Location().String()
+	if name == "" || name == "UTC" {
+		return ""
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return ""
+	}
+	_, offset := t.Zone()
+	if _, loaded := t.In(location).Zone(); loaded != offset {
+		return ""
+	}
+	return
+}
+
+func marshalNode(node expression.Node) (jsonN *jsonNode, err error) {
+	switch node.(type) {
+	case expression.NumberNode:
+		jsonN = &jsonNode{Node: jsonNodeNumber}
+		jsonN.Value, err = marshalValue(node.(expression.NumberNode).Value)
+	case expression.StringNode:
+		jsonN = &jsonNode{Node: jsonNodeString}
+		jsonN.Value, err = marshalValue(node.(expression.StringNode).Value)
+	case expression.BoolNode:
+		jsonN = &jsonNode{Node: jsonNodeBool}
+		jsonN.Value, err = marshalValue(node.(expression.BoolNode).Value)
+	case expression.ColumnNode:
+		jsonN = &jsonNode{Node: jsonNodeColumn, Name: node.(expression.ColumnNode).Name}
+	case expression.UnaryNode:
+		unary := node.(expression.UnaryNode)
+		jsonN = &jsonNode{Node: jsonNodeUnary, Op: unary.Op}
+		jsonN.Operand, err = marshalNode(unary.Operand)
+	case expression.BinaryNode:
+		binary := node.(expression.BinaryNode)
+		jsonN = &jsonNode{Node: jsonNodeBinary, Op: binary.Op}
+		jsonN.Lhs, err = marshalNode(binary.Lhs)
+		if err != nil {
+			return
+		}
+		jsonN.Rhs, err = marshalNode(binary.Rhs)
+	case expression.CallNode:
+		call := node.(expression.CallNode)
+		jsonN = &jsonNode{Node: jsonNodeCall, Name: call.Func}
+		jsonN.Args = make([]*jsonNode, len(call.Args))
+		for i, arg := range call.Args {
+			jsonN.Args[i], err = marshalNode(arg)
+			if err != nil {
+				return
+			}
+		}
+	default:
+		err = errors.New(fmt.Sprintf("expression %v can't be marshaled", node))
+	}
+	return
+}
+
+func unmarshalNode(jsonN *jsonNode) (node expression.Node, err error) {
+	if jsonN == nil {
+		err = errors.New("missing expression node")
+		return
+	}
+	switch jsonN.Node {
+	case jsonNodeNumber:
+		value, e := unmarshalValue(jsonN.Value)
+		_, isInt := value.(int64)
+		_, isFloat := value.(float64)
+		if e != nil || (!isInt && !isFloat) {
+			err = errors.New(fmt.Sprintf("invalid number node value %v", value))
+			return
+		}
+		node = expression.NumberNode{Value: value}
+	case jsonNodeString:
+		value, e := unmarshalValue(jsonN.Value)
+		stringValue, ok := value.(string)
+		if e != nil || !ok {
+			err = errors.New(fmt.Sprintf("invalid string node value %v", value))
+			return
+		}
+		node = expression.StringNode{Value: stringValue}
+	case jsonNodeBool:
+		value, e := unmarshalValue(jsonN.Value)
+		boolValue, ok := value.(bool)
+		if e != nil || !ok {
+			err = errors.New(fmt.Sprintf("invalid bool node value %v", value))
+			return
+		}
+		node = expression.BoolNode{Value: boolValue}
+	case jsonNodeColumn:
+		node = expression.ColumnNode{Name: jsonN.Name}
+	case jsonNodeUnary:
+		operand, e := unmarshalNode(jsonN.Operand)
+		if e != nil {
+			err = e
+			return
+		}
+		node = expression.UnaryNode{Op: jsonN.Op, Operand: operand}
+	case jsonNodeBinary:
+		lhs, e := unmarshalNode(jsonN.Lhs)
+		if e != nil {
+			err = e
+			return
+		}
+		rhs, e := unmarshalNode(jsonN.Rhs)
+		if e != nil {
+			err = e
+			return
+		}
+		node = expression.BinaryNode{Op: jsonN.Op, Lhs: lhs, Rhs: rhs}
+	case jsonNodeCall:
+		call := expression.CallNode{Func: jsonN.Name}
+		call.Args = make([]expression.Node, len(jsonN.Args))
+		for i, arg := range jsonN.Args {
+			call.Args[i], err = unmarshalNode(arg)
+			if err != nil {
+				return
+			}
+		}
+		node = call
+	default:
+		err = errors.New(fmt.Sprintf("unknown expression node %q", jsonN.Node))
+	}
+	return
+}
@@ -0,0 +1,199 @@
+package condition
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConditionJSONRoundTrip(t *testing.T) {
+	budget, _ := ColExpr("abs(budget) * 0.9 - 1")
+	inner := NewCondition(ConditionTypeDataFrame)
+	inner.And(ComparatorEq, 2.0, "ratio").Or(ComparatorIsNan, nil, "ratio").SetMissing(MissingTrue)
+	cond := NewCondition(ConditionTypeDataFrame)
+	cond.And(ComparatorGTE, 18, "age").
+		AndNot(ComparatorIn, []string{"DE", "FR"}, "country").
+		OrNotCond(inner).
+		And(ComparatorBetween, Between(1, math.Inf(1)), "score").
+		And(ComparatorLT, budget, "actual").
+		Or(ComparatorEq, true, "active").
+		And(ComparatorNotEq, math.NaN(), "x")
+
+	data, err := json.Marshal(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(Condition)
+	if err = json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.String() != cond.String() {
+		t.Errorf("got %s, want %s", decoded, cond)
+	}
+	again, _ := json.Marshal(decoded)
+	if string(again) != string(data) {
+		t.Errorf("got %s, want %s", again, data)
+	}
+
+	tokens := decoded.ast.tokens
+	if v := tokens[0].cond.CompItem.Value; v != int64(18) {
+		t.Errorf("int literal decoded as %T %v", v, v)
+	}
+	if v := tokens[4].cond.Cond.ast.tokens[0].cond.CompItem.Value; v != 2.0 {
+		t.Errorf("float literal decoded as %T %v", v, v)
+	}
+	r := tokens[6].cond.CompItem.Value.(Range)
+	if r.Low != int64(1) || !math.IsInf(r.High.(float64), 1) {
+		t.Errorf("range decoded as %v", r)
+	}
+	if _, ok := tokens[8].cond.CompItem.Value.(ColumnRef); !ok {
+		t.Errorf("expression decoded as %v", tokens[8].cond.CompItem.Value)
+	}
+	if !tokens[2].cond.IsNot || !tokens[4].cond.IsNot || decoded.condType != ConditionTypeDataFrame {
+		t.Error("negation or condition type lost")
+	}
+	if tokens[4].cond.Cond.Missing() != MissingTrue || decoded.Missing() != MissingUnknown {
+		t.Error("missing policy lost")
+	}
+	got, _ := tokens[2].cond.CompareString("DE")
+	if !got {
+		t.Error("decoded in set should be prepared")
+	}
+}
+
+func TestConditionJSONSchema(t *testing.T) {
+	cond := NewCondition(ConditionTypeSeries)
+	cond.And(ComparatorGT, 1).OrNot(ComparatorContains, "a")
+	data, err := json.Marshal(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"series","terms":[` +
+		`{"comparator":"\u003e","value":{"type":"int","value":1}},` +
+		`{"op":"||","not":true,"comparator":"contains","value":{"type":"string","value":"a"}}]}`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+func TestConditionJSONError(t *testing.T) {
+	cond := NewCondition(ConditionTypeSeries)
+	cond.AndFunc("", func(ElementValue) bool { return true })
+	if _, err := json.Marshal(cond); err == nil {
+		t.Error("func condition should not marshal")
+	}
+
+	invalid := []string{
+		`{"type":"table","terms":[]}`,
+		`{"type":"series","terms":[{"op":"&&","comparator":">","value":{"type":"int","value":1}}]}`,
+		`{"type":"series","terms":[{"comparator":">","value":{"type":"int","value":1.5}}]}`,
+		`{"type":"series","terms":[{"comparator":">","value":{"type":"date","value":1}}]}`,
+		`{"type":"series","terms":[{"comparator":">"}]}`,
+	}
+	for _, data := range invalid {
+		if err := json.Unmarshal([]byte(data), new(Condition)); err == nil {
+			t.Errorf("%s should fail", data)
+		}
+	}
+}
+
+func TestConditionJSONDatetime(t *testing.T) {
+	ts := time.Date(2020, time.March, 1, 9, 30, 0, 123456789, time.FixedZone("", 2*3600))
+	cond := NewCondition(ConditionTypeDataFrame)
+	cond.Or(ComparatorGT, ts, "Time").And(ComparatorBetween, Between(ts, ts.Add(time.Hour)), "Time")
+	data, err := json.Marshal(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `{"type":"datetime","value":"2020-03-01T09:30:00.123456789+02:00"}`) {
+		t.Errorf("got %s", data)
+	}
+	decoded := new(Condition)
+	if err = json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := decoded.ast.tokens[0].cond.CompItem.Value.(time.Time)
+	if !ok || !got.Equal(ts) || got.Format(time.RFC3339Nano) != ts.Format(time.RFC3339Nano) {
+		t.Errorf("datetime decoded as %T %v", decoded.ast.tokens[0].cond.CompItem.Value, got)
+	}
+	again, _ := json.Marshal(decoded)
+	if string(again) != string(data) {
+		t.Errorf("got %s, want %s", again, data)
+	}
+	if err = json.Unmarshal([]byte(`{"type":"series","terms":[{"comparator":">","value":{"type":"datetime","value":"2020-03-01"}}]}`), decoded); err == nil {
+		t.Error("a datetime without time should fail")
+	}
+}
+
+func TestConditionJSONDatetimeLocation(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	// Both sides of a daylight saving time change.
+	winter := time.Date(2020, time.March, 1, 9, 30, 0, 5, location)
+	summer := time.Date(2020, time.July, 1, 9, 30, 0, 0, location)
+	cond := NewCondition(ConditionTypeDataFrame)
+	cond.Or(ComparatorBetween, Between(winter, summer), "Time")
+	data, err := json.Marshal(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `{"type":"datetime","value":"2020-03-01T09:30:00.000000005-05:00","location":"America/New_York"}`) {
+		t.Errorf("got %s", data)
+	}
+	decoded := new(Condition)
+	if err = json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	r := decoded.ast.tokens[0].cond.CompItem.Value.(Range)
+	for i, want := range []time.Time{winter, summer} {
+		got := []interface{}{r.Low, r.High}[i].(time.Time)
+		if !got.Equal(want) || got.Location().String() != "America/New_York" || got.Format(time.RFC3339Nano) != want.Format(time.RFC3339Nano) {
+			t.Errorf("datetime decoded as %v in %s, want %v", got, got.Location(), want)
+		}
+	}
+
+	fixed := time.Date(2020, time.March, 1, 9, 30, 0, 0, time.FixedZone("", 5*3600+1800))
+	data, _ = json.Marshal(NewCondition(ConditionTypeSeries).Or(ComparatorGT, fixed))
+	if strings.Contains(string(data), "location") {
+		t.Errorf("fixed zone: got %s", data)
+	}
+	invalid := `{"type":"series","terms":[{"comparator":">","value":{"type":"datetime","value":"2020-03-01T09:30:00Z","location":"Nowhere/Town"}}]}`
+	if err = json.Unmarshal([]byte(invalid), decoded); err == nil {
+		t.Error("an unknown location should fail")
+	}
+}
+
+func TestConditionJSONDuration(t *testing.T) {
+	cond := NewCondition(ConditionTypeDataFrame)
+	cond.Or(ComparatorGTE, 90*time.Minute+time.Nanosecond, "Latency").
+		And(ComparatorIn, []time.Duration{time.Second, -time.Hour}, "Latency")
+	data, err := json.Marshal(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `{"type":"duration","value":5400000000001}`) {
+		t.Errorf("got %s", data)
+	}
+	decoded := new(Condition)
+	if err = json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if v := decoded.ast.tokens[0].cond.CompItem.Value; v != 90*time.Minute+time.Nanosecond {
+		t.Errorf("duration decoded as %T %v", v, v)
+	}
+	got, _ := decoded.ast.tokens[2].cond.CompareDuration(-time.Hour)
+	if !got {
+		t.Error("decoded duration set should hold -1h")
+	}
+	again, _ := json.Marshal(decoded)
+	if string(again) != string(data) {
+		t.Errorf("got %s, want %s", again, data)
+	}
+	if err = json.Unmarshal([]byte(`{"type":"series","terms":[{"comparator":">","value":{"type":"duration","value":"1h"}}]}`), decoded); err == nil {
+		t.Error("a duration string should fail")
+	}
+}
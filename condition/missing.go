@@ -0,0 +1,83 @@
+package condition
+
+// Missing policies decide what comparing a missing (NaN) element yields.
+// is_nan, not_nan and custom predicates see missing elements as they are
+// and aren't affected.
+const (
+	// MissingUnknown makes the comparison unknown, like NULL in SQL. && and
+	// || follow three-valued logic, !unknown is unknown, and filters only
+	// keep the elements for which the whole condition is true.
+	MissingUnknown = iota
+	// MissingFalse makes the comparison false, so !(a > 1) holds for a
+	// missing a.
+	MissingFalse
+	// MissingTrue makes the comparison true.
+	MissingTrue
+)
+
+// SetMissing sets the missing policy of the comparisons of cond. Sub
+// conditions keeping the default MissingUnknown inherit the policy of the
+// condition they're part of.
+func (cond *Condition) SetMissing(missing int) *Condition {
+	cond.missing = missing
+	return cond
+}
+
+// Missing returns the missing policy set by SetMissing.
+func (cond *Condition) Missing() int {
+	return cond.missing
+}
+
+// InheritMissing returns the missing policy of cond as a sub condition of a
+// condition with the policy missing.
+func (cond *Condition) InheritMissing(missing int) int {
+	if cond.missing != MissingUnknown {
+		return cond.missing
+	}
+	return missing
+}
+
+// ComparesMissing reports whether condVal is a comparison of values, whose
+// result for a missing element follows the missing policy.
+func (condVal *CondValue) ComparesMissing() bool {
+	if condVal.Cond != nil {
+		return false
+	}
+	switch condVal.CompItem.Comparator {
+	case ComparatorIsNan, ComparatorNotNan, ComparatorFunc, ComparatorRowFunc:
+		return false
+	}
+	return true
+}
+
+// CompareMissing is the result of comparing a missing element under the
+// missing policy missing, unknown reporting an unknown result.
+func CompareMissing(missing int) (result, unknown bool) {
+	switch missing {
+	case MissingFalse:
+		return false, false
+	case MissingTrue:
+		return true, false
+	}
+	return false, true
+}
+
+// KleeneAnd, KleeneOr and KleeneNot combine truth values in three-valued
+// logic, unknown values having result false.
+func KleeneAnd(l, lUnknown, r, rUnknown bool) (result, unknown bool) {
+	if (!l && !lUnknown) || (!r && !rUnknown) {
+		return false, false
+	}
+	return l && r, lUnknown || rUnknown
+}
+
+func KleeneOr(l, lUnknown, r, rUnknown bool) (result, unknown bool) {
+	if l || r {
+		return true, false
+	}
+	return false, lUnknown || rUnknown
+}
+
+func KleeneNot(value, unknown bool) (result, resultUnknown bool) {
+	return !value && !unknown, unknown
+}
@@ -0,0 +1,55 @@
+package condition
+
+// ElementValue is the element handed to a custom predicate. It's implemented
+// by the element values of series and dataframes. NaN reports a missing
+// element, null or a float NaN, and Null only a null one.
+type ElementValue interface {
+	Int() (int64, error)
+	Float() (float64, error)
+	String() (string, error)
+	Bool() (bool, error)
+	Interface() (interface{}, error)
+	NaN() bool
+	Null() bool
+}
+
+// Row maps the column names of a dataframe row to its elements.
+type Row map[string]ElementValue
+
+// ElementFunc is the value of a ComparatorFunc comparison.
+type ElementFunc func(ElementValue) bool
+
+func (f ElementFunc) String() string {
+	return "<func>"
+}
+
+// RowFunc is the value of a ComparatorRowFunc comparison.
+type RowFunc func(Row) bool
+
+func (f RowFunc) String() string {
+	return "<func>"
+}
+
+// AndFunc appends "&& f(column)". For series conditions the column is
+// ignored and f receives the element of the series.
+func (cond *Condition) AndFunc(column string, f func(ElementValue) bool) *Condition {
+	condVal := newCompCondValue(ComparatorFunc, ElementFunc(f), []string{column}, false)
+	return cond.appendCondValue(tokenOperatorAnd, condVal)
+}
+
+func (cond *Condition) OrFunc(column string, f func(ElementValue) bool) *Condition {
+	condVal := newCompCondValue(ComparatorFunc, ElementFunc(f), []string{column}, false)
+	return cond.appendCondValue(tokenOperatorOr, condVal)
+}
+
+// AndRowFunc appends "&& f(row)", where row holds every column of the
+// dataframe. Row functions are only supported in dataframe conditions.
+func (cond *Condition) AndRowFunc(f func(Row) bool) *Condition {
+	condVal := newCompCondValue(ComparatorRowFunc, RowFunc(f), nil, false)
+	return cond.appendCondValue(tokenOperatorAnd, condVal)
+}
+
+func (cond *Condition) OrRowFunc(f func(Row) bool) *Condition {
+	condVal := newCompCondValue(ComparatorRowFunc, RowFunc(f), nil, false)
+	return cond.appendCondValue(tokenOperatorOr, condVal)
+}
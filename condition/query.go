@@ -0,0 +1,288 @@
+package condition
+
+import (
+	"fmt"
+	"github.com/hunknownz/godas/expression"
+	"strconv"
+)
+
+var queryComparators = map[string]string{
+	"=":                      ComparatorEq,
+	"==":                     ComparatorEq,
+	"!=":                     ComparatorNotEq,
+	"<":                      ComparatorLT,
+	"<=":                     ComparatorLTE,
+	">":                      ComparatorGT,
+	">=":                     ComparatorGTE,
+	ComparatorIn:             ComparatorIn,
+	ComparatorNotIn:          ComparatorNotIn,
+	ComparatorIsNan:          ComparatorIsNan,
+	ComparatorNotNan:         ComparatorNotNan,
+	ComparatorBetween:        ComparatorBetween,
+	ComparatorContains:       ComparatorContains,
+	ComparatorStartsWith:     ComparatorStartsWith,
+	ComparatorEndsWith:       ComparatorEndsWith,
+	ComparatorRegex:          ComparatorRegex,
+	ComparatorEqFold:         ComparatorEqFold,
+	ComparatorContainsFold:   ComparatorContainsFold,
+	ComparatorStartsWithFold: ComparatorStartsWithFold,
+	ComparatorEndsWithFold:   ComparatorEndsWithFold,
+	ComparatorRegexFold:      ComparatorRegexFold,
+}
+
+type queryParser struct {
+	tokens []expression.Token
+	pos    int
+}
+
+func newQueryError(pos int, format string, args ...interface{}) *expression.ParseError {
+	return &expression.ParseError{
+		Pos: pos,
+		Msg: fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *queryParser) peek() expression.Token {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() expression.Token {
+	token := p.tokens[p.pos]
+	if token.Type != expression.TokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+func (p *queryParser) isOperator(text string) bool {
+	token := p.peek()
+	return token.Type == expression.TokenOperator && token.Text == text
+}
+
+// parseCondition parses terms joined by && and ||. The operators are kept
+// flat in the condition, whose AST applies the precedence.
+func (p *queryParser) parseCondition() (cond *Condition, err error) {
+	cond = NewCondition(ConditionTypeDataFrame)
+	operatorType := tokenOperatorAnd
+	for {
+		condVal, e := p.parseTerm()
+		if e != nil {
+			err = e
+			return
+		}
+		cond.appendCondValue(operatorType, condVal)
+
+		switch {
+		case p.isOperator(operatorAnd):
+			operatorType = tokenOperatorAnd
+		case p.isOperator(operatorOr):
+			operatorType = tokenOperatorOr
+		default:
+			return
+		}
+		p.next()
+	}
+}
+
+func (p *queryParser) parseTerm() (condVal *CondValue, err error) {
+	token := p.peek()
+	if token.Type == expression.TokenOperator && token.Text == "!" {
+		p.next()
+		condVal, err = p.parseTerm()
+		if err != nil {
+			return
+		}
+		condVal.IsNot = !condVal.IsNot
+		return
+	}
+	if token.Type == expression.TokenLeftParen {
+		p.next()
+		cond, e := p.parseCondition()
+		if e != nil {
+			err = e
+			return
+		}
+		closing := p.next()
+		if closing.Type != expression.TokenRightParen {
+			err = newQueryError(closing.Pos, "expected \")\", found %s", closing)
+			return
+		}
+		condVal = &CondValue{
+			Cond: cond,
+		}
+		return
+	}
+	return p.parseComparison()
+}
+
+func (p *queryParser) parseComparison() (condVal *CondValue, err error) {
+	column := p.next()
+	if column.Type != expression.TokenIdent {
+		err = newQueryError(column.Pos, "expected column name, found %s", column)
+		return
+	}
+
+	token := p.next()
+	text := token.Text
+	if token.Type == expression.TokenIdent && text == "not" && p.peek().Text == ComparatorIn {
+		p.next()
+		text = ComparatorNotIn
+	}
+	comparator, ok := queryComparators[text]
+	if !ok || token.Quoted || (token.Type != expression.TokenOperator && token.Type != expression.TokenIdent) {
+		err = newQueryError(token.Pos, "expected comparator, found %s", token)
+		return
+	}
+
+	var value interface{}
+	switch comparator {
+	case ComparatorIsNan, ComparatorNotNan:
+	case ComparatorIn, ComparatorNotIn:
+		value, err = p.parseList()
+	case ComparatorBetween:
+		var values []interface{}
+		values, err = p.parseList()
+		if err == nil && len(values) != 2 {
+			err = newQueryError(token.Pos, "between expects 2 values, found %d", len(values))
+		}
+		if err == nil {
+			value = Between(values[0], values[1])
+		}
+	case ComparatorEq, ComparatorNotEq, ComparatorLT, ComparatorLTE, ComparatorGT, ComparatorGTE:
+		value, err = p.parseOperand()
+	default:
+		value, err = p.parseLiteral()
+	}
+	if err != nil {
+		return
+	}
+	condVal = newCompCondValue(comparator, value, []string{column.Text}, false)
+	return
+}
+
+// parseOperand parses the right side of an ordered comparison. A literal is
+// returned as is, an expression over columns such as "budget * 0.9" as a
+// ColumnRef.
+func (p *queryParser) parseOperand() (value interface{}, err error) {
+	node, end, err := expression.ParseOperand(p.tokens, p.pos)
+	if err != nil {
+		return
+	}
+	p.pos = end
+	switch node.(type) {
+	case expression.NumberNode:
+		value = node.(expression.NumberNode).Value
+	case expression.StringNode:
+		value = node.(expression.StringNode).Value
+	case expression.BoolNode:
+		value = node.(expression.BoolNode).Value
+	default:
+		value = ColumnRef{
+			Expr: node,
+		}
+	}
+	return
+}
+
+// parseList parses "(literal, literal, ...)".
+func (p *queryParser) parseList() (values []interface{}, err error) {
+	token := p.next()
+	if token.Type != expression.TokenLeftParen {
+		err = newQueryError(token.Pos, "expected \"(\", found %s", token)
+		return
+	}
+	values = make([]interface{}, 0)
+	if p.peek().Type == expression.TokenRightParen {
+		p.next()
+		return
+	}
+	for {
+		value, e := p.parseLiteral()
+		if e != nil {
+			err = e
+			return
+		}
+		values = append(values, value)
+		token = p.next()
+		if token.Type == expression.TokenRightParen {
+			return
+		}
+		if token.Type != expression.TokenComma {
+			err = newQueryError(token.Pos, "expected \",\" or \")\", found %s", token)
+			return
+		}
+	}
+}
+
+func (p *queryParser) parseLiteral() (value interface{}, err error) {
+	token := p.next()
+	negative := false
+	if token.Type == expression.TokenOperator && token.Text == "-" {
+		negative = true
+		token = p.next()
+		if token.Type != expression.TokenNumber {
+			err = newQueryError(token.Pos, "expected number, found %s", token)
+			return
+		}
+	}
+
+	switch token.Type {
+	case expression.TokenNumber:
+		if intValue, e := strconv.ParseInt(token.Text, 10, 64); e == nil {
+			if negative {
+				intValue = -intValue
+			}
+			value = intValue
+			return
+		}
+		floatValue, e := strconv.ParseFloat(token.Text, 64)
+		if e != nil {
+			err = newQueryError(token.Pos, "invalid number %s", token)
+			return
+		}
+		if negative {
+			floatValue = -floatValue
+		}
+		value = floatValue
+	case expression.TokenString:
+		value = token.Text
+	case expression.TokenIdent:
+		if !token.Quoted && (token.Text == "true" || token.Text == "false") {
+			value = token.Text == "true"
+			return
+		}
+		err = newQueryError(token.Pos, "expected literal, found %s", token)
+	default:
+		err = newQueryError(token.Pos, "expected literal, found %s", token)
+	}
+	return
+}
+
+// ParseQuery parses a query such as "age >= 18 && country in ('DE', 'FR')"
+// into a dataframe condition. Columns containing spaces are quoted with
+// backticks. The comparators = == != < <= > >= take a literal or an
+// expression over other columns, as in "actual < budget * 0.9". The word
+// comparators (contains, regex, ieq, ...) take a literal, "in", "not in"
+// and "between" a parenthesized list, is_nan and not_nan no value. Terms
+// are combined with !, parentheses, && and ||, && binding tighter than ||.
+// Malformed queries return an *expression.ParseError with the position.
+func ParseQuery(query string) (cond *Condition, err error) {
+	tokens, err := expression.Tokenize(query)
+	if err != nil {
+		return
+	}
+	p := &queryParser{
+		tokens: tokens,
+	}
+	cond, err = p.parseCondition()
+	if err != nil {
+		cond = nil
+		return
+	}
+	token := p.peek()
+	if token.Type != expression.TokenEOF {
+		cond = nil
+		err = newQueryError(token.Pos, "unexpected %s", token)
+	}
+	return
+}
@@ -0,0 +1,83 @@
+package condition
+
+import (
+	"errors"
+	"github.com/hunknownz/godas/expression"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"age >= 18", "(age >= 18)"},
+		{"age >= 18 && country in ('DE', \"FR\")", "(age >= 18)&&(country in [DE FR])"},
+		{"`last name` startswith 'Mc' || !(score < -1.5)", "(last name startswith Mc)||!((score < -1.5))"},
+		{"active == true && id not in (1, 2)", "(active = true)&&(id not in [1 2])"},
+		{"!price is_nan", "!(price is_nan <nil>)"},
+		{"a = 1 || b = 2 && c = 3", "(a = 1)||(b = 2)&&(c = 3)"},
+		{"shipped_at > ordered_at", "(shipped_at > `ordered_at`)"},
+		{"actual < budget * 0.9 && x = -2", "(actual < (`budget` * 0.9))&&(x = -2)"},
+	}
+	for _, c := range cases {
+		cond, err := ParseQuery(c.query)
+		if err != nil {
+			t.Errorf("%s: %v", c.query, err)
+			continue
+		}
+		if got := cond.String(); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.query, got, c.want)
+		}
+	}
+}
+
+func TestParseQueryBetween(t *testing.T) {
+	cond, err := ParseQuery("x between (1, 2.5)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmp := cond.Prepare().(ValueExprAST).Value.CompItem
+	if cmp.Value != (Range{Low: int64(1), High: 2.5, IncludeLow: true, IncludeHigh: true}) {
+		t.Errorf("unexpected between value %v", cmp.Value)
+	}
+}
+
+func TestParseQueryPrecedence(t *testing.T) {
+	cond, _ := ParseQuery("a = 1 || b = 2 && c = 3")
+	root, ok := cond.Prepare().(BinaryExprAST)
+	if !ok || root.Op != operatorOr {
+		t.Fatalf("expected || at the root, got %v", cond.Prepare())
+	}
+	if rhs, ok := root.Rhs.(BinaryExprAST); !ok || rhs.Op != operatorAnd {
+		t.Errorf("expected && on the right, got %v", root.Rhs)
+	}
+}
+
+func TestParseQueryError(t *testing.T) {
+	cases := []struct {
+		query string
+		pos   int
+	}{
+		{"", 0},
+		{"age >", 5},
+		{"age ~ 1", 4},
+		{"(age > 1", 8},
+		{"age in 1", 7},
+		{"age in (1 2)", 10},
+		{"age > 1 )", 8},
+		{"name = 'x", 7},
+		{"x between (1)", 2},
+	}
+	for _, c := range cases {
+		_, err := ParseQuery(c.query)
+		var parseErr *expression.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%q: expected parse error, got %v", c.query, err)
+			continue
+		}
+		if parseErr.Pos != c.pos {
+			t.Errorf("%q: got position %d, want %d", c.query, parseErr.Pos, c.pos)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package condition
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/expression"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DialectPostgres = iota
+	DialectMySQL
+	DialectSQLite
+)
+
+// likeEscape escapes the LIKE wildcards of pattern values. It's passed with
+// ESCAPE, since backslash isn't an escape character in every dialect.
+const likeEscape = "!"
+
+var sqlOperators = map[string]string{
+	ComparatorEq:    "=",
+	ComparatorNotEq: "<>",
+	ComparatorLT:    "<",
+	ComparatorLTE:   "<=",
+	ComparatorGT:    ">",
+	ComparatorGTE:   ">=",
+}
+
+type sqlBuilder struct {
+	dialect int
+	args    []interface{}
+}
+
+func (b *sqlBuilder) quoteIdent(ident string) (quoted string, err error) {
+	if ident == "" || strings.ContainsRune(ident, 0) {
+		err = errors.New(fmt.Sprintf("invalid column name %q", ident))
+		return
+	}
+	quote := `"`
+	if b.dialect == DialectMySQL {
+		quote = "`"
+	}
+	quoted = quote + strings.Replace(ident, quote, quote+quote, -1) + quote
+	return
+}
+
+// bind appends value to the args and returns its placeholder.
+func (b *sqlBuilder) bind(value interface{}) string {
+	b.args = append(b.args, value)
+	if b.dialect == DialectPostgres {
+		return "$" + strconv.Itoa(len(b.args))
+	}
+	return "?"
+}
+
+// build translates expr, whose comparisons with NULL follow the missing
+// policy missing.
+func (b *sqlBuilder) build(expr ExprAST, missing int) (sql string, err error) {
+	switch expr.(type) {
+	case BinaryExprAST:
+		ast := expr.(BinaryExprAST)
+		lhs, e := b.build(ast.Lhs, missing)
+		if e != nil {
+			err = e
+			return
+		}
+		rhs, e := b.build(ast.Rhs, missing)
+		if e != nil {
+			err = e
+			return
+		}
+		operator := "AND"
+		if ast.Op == operatorOr {
+			operator = "OR"
+		}
+		sql = fmt.Sprintf("(%s %s %s)", lhs, operator, rhs)
+	case ValueExprAST:
+		condVal := expr.(ValueExprAST).Value
+		if condVal.Cond != nil {
+			sql, err = b.build(condVal.Cond.Prepare(), condVal.Cond.InheritMissing(missing))
+		} else {
+			sql, err = b.buildCompItem(condVal.CompItem)
+			if err == nil && condVal.ComparesMissing() {
+				sql = resolveMissingSQL(sql, missing)
+			}
+		}
+		if err == nil && condVal.IsNot {
+			sql = fmt.Sprintf("NOT (%s)", sql)
+		}
+	case nil:
+		sql = "1 = 1"
+	default:
+		err = errors.New(fmt.Sprintf("unknown expression %v", expr))
+	}
+	return
+}
+
+// resolveMissingSQL turns the NULL result of the comparison sql into false
+// or true according to the missing policy missing.
+func resolveMissingSQL(sql string, missing int) string {
+	switch missing {
+	case MissingFalse:
+		return fmt.Sprintf("COALESCE(%s, 1 = 0)", sql)
+	case MissingTrue:
+		return fmt.Sprintf("COALESCE(%s, 1 = 1)", sql)
+	}
+	return sql
+}
+
+func (b *sqlBuilder) buildCompItem(item *CompItem) (sql string, err error) {
+	column, err := b.quoteIdent(item.Column)
+	if err != nil {
+		return
+	}
+
+	switch item.Comparator {
+	case ComparatorEq, ComparatorNotEq, ComparatorLT, ComparatorLTE, ComparatorGT, ComparatorGTE:
+		var value string
+		if ref, ok := item.Value.(ColumnRef); ok {
+			value, err = b.buildNode(ref.Expr)
+		} else {
+			value, err = b.bindLiteral(item.Value)
+		}
+		if err != nil {
+			return
+		}
+		sql = fmt.Sprintf("%s %s %s", column, sqlOperators[item.Comparator], value)
+	case ComparatorIn, ComparatorNotIn:
+		values, ok := item.Value.([]interface{})
+		if !ok {
+			err = errors.New(fmt.Sprintf("%s value %v must be a slice", item.Comparator, item.Value))
+			return
+		}
+		if len(values) == 0 {
+			sql = "1 = 0"
+			if item.Comparator == ComparatorNotIn {
+				sql = "1 = 1"
+			}
+			return
+		}
+		placeholders := make([]string, len(values))
+		for i, value := range values {
+			placeholders[i], err = b.bindLiteral(value)
+			if err != nil {
+				return
+			}
+		}
+		operator := "IN"
+		if item.Comparator == ComparatorNotIn {
+			operator = "NOT IN"
+		}
+		sql = fmt.Sprintf("%s %s (%s)", column, operator, strings.Join(placeholders, ", "))
+	case ComparatorIsNan:
+		sql = fmt.Sprintf("%s IS NULL", column)
+	case ComparatorNotNan:
+		sql = fmt.Sprintf("%s IS NOT NULL", column)
+	case ComparatorBetween:
+		r, ok := item.Value.(Range)
+		if !ok {
+			err = errors.New(fmt.Sprintf("between value %v must be a condition.Range", item.Value))
+			return
+		}
+		low, e := b.bindLiteral(r.Low)
+		if e != nil {
+			err = e
+			return
+		}
+		high, e := b.bindLiteral(r.High)
+		if e != nil {
+			err = e
+			return
+		}
+		if r.IncludeLow && r.IncludeHigh {
+			sql = fmt.Sprintf("%s BETWEEN %s AND %s", column, low, high)
+			return
+		}
+		lowOperator, highOperator := ">", "<"
+		if r.IncludeLow {
+			lowOperator = ">="
+		}
+		if r.IncludeHigh {
+			highOperator = "<="
+		}
+		sql = fmt.Sprintf("(%s %s %s AND %s %s %s)", column, lowOperator, low, column, highOperator, high)
+	case ComparatorEqFold:
+		pattern, e := b.bindString(item)
+		if e != nil {
+			err = e
+			return
+		}
+		sql = fmt.Sprintf("LOWER(%s) = LOWER(%s)", column, pattern)
+	case ComparatorContains, ComparatorStartsWith, ComparatorEndsWith,
+		ComparatorContainsFold, ComparatorStartsWithFold, ComparatorEndsWithFold:
+		sql, err = b.buildLike(column, item)
+	case ComparatorRegex, ComparatorRegexFold:
+		sql, err = b.buildRegex(column, item)
+	default:
+		err = errors.New(fmt.Sprintf("comparator %s can't be translated to sql", item.Comparator))
+	}
+	return
+}
+
+func (b *sqlBuilder) bindLiteral(value interface{}) (placeholder string, err error) {
+	switch value.(type) {
+	case int64, float64, string, bool, time.Time, time.Duration:
+		placeholder = b.bind(value)
+	default:
+		err = errors.New(fmt.Sprintf("value %v of type %T can't be translated to sql", value, value))
+	}
+	return
+}
+
+func (b *sqlBuilder) bindString(item *CompItem) (placeholder string, err error) {
+	value, ok := item.Value.(string)
+	if !ok {
+		err = errors.New(fmt.Sprintf("%s value %v must be a string", item.Comparator, item.Value))
+		return
+	}
+	placeholder = b.bind(value)
+	return
+}
+
+// buildLike translates the contains, startswith and endswith comparators.
+// LIKE ignores case in SQLite and in the default MySQL collations, so the
+// case-sensitive comparators use GLOB in SQLite and LIKE BINARY in MySQL.
+func (b *sqlBuilder) buildLike(column string, item *CompItem) (sql string, err error) {
+	value, ok := item.Value.(string)
+	if !ok {
+		err = errors.New(fmt.Sprintf("%s value %v must be a string", item.Comparator, item.Value))
+		return
+	}
+	fold := false
+	switch item.Comparator {
+	case ComparatorContainsFold, ComparatorStartsWithFold, ComparatorEndsWithFold:
+		fold = true
+	}
+	wildcard := "%"
+	replacer := strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+	if b.dialect == DialectSQLite && !fold {
+		wildcard = "*"
+		replacer = strings.NewReplacer("*", "[*]", "?", "[?]", "[", "[[]")
+	}
+	pattern := replacer.Replace(value)
+	switch item.Comparator {
+	case ComparatorContains, ComparatorContainsFold:
+		pattern = wildcard + pattern + wildcard
+	case ComparatorStartsWith, ComparatorStartsWithFold:
+		pattern = pattern + wildcard
+	case ComparatorEndsWith, ComparatorEndsWithFold:
+		pattern = wildcard + pattern
+	}
+	placeholder := b.bind(pattern)
+	switch {
+	case fold:
+		sql = fmt.Sprintf("LOWER(%s) LIKE LOWER(%s) ESCAPE '%s'", column, placeholder, likeEscape)
+	case b.dialect == DialectSQLite:
+		sql = fmt.Sprintf("%s GLOB %s", column, placeholder)
+	case b.dialect == DialectMySQL:
+		sql = fmt.Sprintf("%s LIKE BINARY %s ESCAPE '%s'", column, placeholder, likeEscape)
+	default:
+		sql = fmt.Sprintf("%s LIKE %s ESCAPE '%s'", column, placeholder, likeEscape)
+	}
+	return
+}
+
+func (b *sqlBuilder) buildRegex(column string, item *CompItem) (sql string, err error) {
+	pattern, err := b.bindString(item)
+	if err != nil {
+		return
+	}
+	fold := item.Comparator == ComparatorRegexFold
+	switch {
+	case b.dialect == DialectPostgres && fold:
+		sql = fmt.Sprintf("%s ~* %s", column, pattern)
+	case b.dialect == DialectPostgres:
+		sql = fmt.Sprintf("%s ~ %s", column, pattern)
+	case b.dialect == DialectMySQL && fold:
+		sql = fmt.Sprintf("REGEXP_LIKE(%s, %s, 'i')", column, pattern)
+	case fold:
+		err = errors.New("iregex can't be translated to sqlite")
+	default:
+		sql = fmt.Sprintf("%s REGEXP %s", column, pattern)
+	}
+	return
+}
+
+// buildNode translates the arithmetic of a column reference.
+func (b *sqlBuilder) buildNode(node expression.Node) (sql string, err error) {
+	switch node.(type) {
+	case expression.NumberNode:
+		sql = b.bind(node.(expression.NumberNode).Value)
+	case expression.StringNode:
+		sql = b.bind(node.(expression.StringNode).Value)
+	case expression.BoolNode:
+		sql = b.bind(node.(expression.BoolNode).Value)
+	case expression.ColumnNode:
+		sql, err = b.quoteIdent(node.(expression.ColumnNode).Name)
+	case expression.UnaryNode:
+		unary := node.(expression.UnaryNode)
+		if unary.Op != "-" {
+			err = errors.New(fmt.Sprintf("operator %q can't be translated to sql", unary.Op))
+			return
+		}
+		operand, e := b.buildNode(unary.Operand)
+		if e != nil {
+			err = e
+			return
+		}
+		sql = fmt.Sprintf("(-%s)", operand)
+	case expression.BinaryNode:
+		binary := node.(expression.BinaryNode)
+		switch binary.Op {
+		case "+", "-", "*", "/", "%":
+		default:
+			err = errors.New(fmt.Sprintf("operator %q can't be translated to sql", binary.Op))
+			return
+		}
+		lhs, e := b.buildNode(binary.Lhs)
+		if e != nil {
+			err = e
+			return
+		}
+		rhs, e := b.buildNode(binary.Rhs)
+		if e != nil {
+			err = e
+			return
+		}
+		sql = fmt.Sprintf("(%s %s %s)", lhs, binary.Op, rhs)
+	case expression.CallNode:
+		call := node.(expression.CallNode)
+		if call.Func != "abs" && call.Func != "round" {
+			err = errors.New(fmt.Sprintf("function %s can't be translated to sql", call.Func))
+			return
+		}
+		args := make([]string, len(call.Args))
+		for i, arg := range call.Args {
+			args[i], err = b.buildNode(arg)
+			if err != nil {
+				return
+			}
+		}
+		sql = fmt.Sprintf("%s(%s)", strings.ToUpper(call.Func), strings.Join(args, ", "))
+	default:
+		err = errors.New(fmt.Sprintf("expression %v can't be translated to sql", node))
+	}
+	return
+}
+
+// ToSQL translates a dataframe condition into a parameterized WHERE clause
+// fragment for dialect, with the values in args. Column names are quoted,
+// is_nan and not_nan map to IS NULL and IS NOT NULL. contains, startswith
+// and endswith are case-sensitive in every dialect, as they're in memory.
+// Custom predicates and conditions without columns can't be translated.
+//
+// Comparisons with NULL are unknown, as comparisons with missing elements
+// are in memory. Missing policies other than MissingUnknown are translated
+// with COALESCE.
+func ToSQL(cond *Condition, dialect int) (where string, args []interface{}, err error) {
+	switch dialect {
+	case DialectPostgres, DialectMySQL, DialectSQLite:
+	default:
+		err = errors.New(fmt.Sprintf("to sql error: unknown dialect %d", dialect))
+		return
+	}
+	b := &sqlBuilder{
+		dialect: dialect,
+	}
+	where, err = b.build(cond.Prepare(), cond.Missing())
+	if err != nil {
+		where = ""
+		err = fmt.Errorf("to sql error: %w", err)
+		return
+	}
+	args = b.args
+	return
+}
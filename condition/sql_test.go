@@ -0,0 +1,139 @@
+package condition
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToSQL(t *testing.T) {
+	inner := NewCondition(ConditionTypeDataFrame)
+	inner.And(ComparatorIsNan, nil, "deleted_at").Or(ComparatorBetween, Between(1, 5), "rank")
+	budget, _ := ColExpr("budget * 0.9")
+	cond := NewCondition(ConditionTypeDataFrame)
+	cond.And(ComparatorGTE, 18, "age").
+		And(ComparatorIn, []string{"DE", "FR"}, "country").
+		OrNotCond(inner).
+		And(ComparatorLT, budget, "actual").
+		And(ComparatorStartsWith, "50%_", `na"me`)
+
+	where, args, err := ToSQL(cond, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `(("age" >= $1 AND "country" IN ($2, $3)) OR (NOT (("deleted_at" IS NULL OR "rank" BETWEEN $4 AND $5)) ` +
+		`AND ("actual" < ("budget" * $6) AND "na""me" LIKE $7 ESCAPE '!')))`
+	if where != want {
+		t.Errorf("got  %s\nwant %s", where, want)
+	}
+	wantArgs := []interface{}{int64(18), "DE", "FR", int64(1), int64(5), 0.9, "50!%!_%"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("got args %v, want %v", args, wantArgs)
+	}
+
+	where, args, _ = ToSQL(cond, DialectMySQL)
+	want = "((`age` >= ? AND `country` IN (?, ?)) OR (NOT ((`deleted_at` IS NULL OR `rank` BETWEEN ? AND ?)) " +
+		"AND (`actual` < (`budget` * ?) AND `na\"me` LIKE BINARY ? ESCAPE '!')))"
+	if where != want || len(args) != 7 {
+		t.Errorf("got  %s\nwant %s", where, want)
+	}
+}
+
+func TestToSQLLikeCase(t *testing.T) {
+	cases := []struct {
+		comparator string
+		dialect    int
+		want       string
+		arg        string
+	}{
+		{ComparatorContains, DialectSQLite, `"c" GLOB ?`, "*a[*]b[?]c[[]d]%_*"},
+		{ComparatorStartsWith, DialectSQLite, `"c" GLOB ?`, "a[*]b[?]c[[]d]%_*"},
+		{ComparatorEndsWith, DialectSQLite, `"c" GLOB ?`, "*a[*]b[?]c[[]d]%_"},
+		{ComparatorContainsFold, DialectSQLite, `LOWER("c") LIKE LOWER(?) ESCAPE '!'`, "%a*b?c[d]!%!_%"},
+		{ComparatorEndsWith, DialectMySQL, "`c` LIKE BINARY ? ESCAPE '!'", "%a*b?c[d]!%!_"},
+		{ComparatorStartsWithFold, DialectMySQL, "LOWER(`c`) LIKE LOWER(?) ESCAPE '!'", "a*b?c[d]!%!_%"},
+		{ComparatorContains, DialectPostgres, `"c" LIKE $1 ESCAPE '!'`, "%a*b?c[d]!%!_%"},
+	}
+	for _, c := range cases {
+		cond := NewCondition(ConditionTypeDataFrame)
+		cond.And(c.comparator, "a*b?c[d]%_", "c")
+		where, args, err := ToSQL(cond, c.dialect)
+		if err != nil {
+			t.Errorf("%s: %v", c.comparator, err)
+			continue
+		}
+		if where != c.want || len(args) != 1 || args[0] != c.arg {
+			t.Errorf("%s on dialect %d: got %s %v, want %s [%s]", c.comparator, c.dialect, where, args, c.want, c.arg)
+		}
+	}
+}
+
+func TestToSQLMissing(t *testing.T) {
+	inner := NewCondition(ConditionTypeDataFrame)
+	inner.And(ComparatorLT, 5, "rank").Or(ComparatorIsNan, nil, "rank").SetMissing(MissingTrue)
+	cond := NewCondition(ConditionTypeDataFrame)
+	cond.AndNot(ComparatorEq, "DE", "country").AndCond(inner).SetMissing(MissingFalse)
+
+	where, _, err := ToSQL(cond, DialectSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `(NOT (COALESCE("country" = ?, 1 = 0)) AND (COALESCE("rank" < ?, 1 = 1) OR "rank" IS NULL))`
+	if where != want {
+		t.Errorf("got  %s\nwant %s", where, want)
+	}
+}
+
+func TestToSQLComparators(t *testing.T) {
+	cases := []struct {
+		comparator string
+		value      interface{}
+		want       string
+	}{
+		{ComparatorNotEq, 1, `"c" <> $1`},
+		{ComparatorNotIn, []int{}, "1 = 1"},
+		{ComparatorNotNan, nil, `"c" IS NOT NULL`},
+		{ComparatorEqFold, "A", `LOWER("c") = LOWER($1)`},
+		{ComparatorContainsFold, "a", `LOWER("c") LIKE LOWER($1) ESCAPE '!'`},
+		{ComparatorRegexFold, "^a", `"c" ~* $1`},
+		{ComparatorBetween, Range{Low: 1, High: 2, IncludeLow: true}, `("c" >= $1 AND "c" < $2)`},
+	}
+	for _, c := range cases {
+		cond := NewCondition(ConditionTypeDataFrame)
+		cond.AndNot(c.comparator, c.value, "c")
+		where, _, err := ToSQL(cond, DialectPostgres)
+		if err != nil {
+			t.Errorf("%s: %v", c.comparator, err)
+			continue
+		}
+		if want := "NOT (" + c.want + ")"; where != want {
+			t.Errorf("%s: got %s, want %s", c.comparator, where, want)
+		}
+	}
+
+	where, args, _ := ToSQL(NewCondition(ConditionTypeDataFrame), DialectSQLite)
+	if where != "1 = 1" || len(args) != 0 {
+		t.Errorf("empty condition: got %s %v", where, args)
+	}
+}
+
+func TestToSQLError(t *testing.T) {
+	series := NewCondition(ConditionTypeSeries)
+	series.And(ComparatorGT, 1)
+	funcs := NewCondition(ConditionTypeDataFrame)
+	funcs.AndFunc("c", func(ElementValue) bool { return true })
+	regex := NewCondition(ConditionTypeDataFrame)
+	regex.And(ComparatorRegexFold, "a", "c")
+
+	if _, _, err := ToSQL(series, DialectPostgres); err == nil {
+		t.Error("condition without column should fail")
+	}
+	if _, _, err := ToSQL(funcs, DialectPostgres); err == nil {
+		t.Error("func condition should fail")
+	}
+	if _, _, err := ToSQL(regex, DialectSQLite); err == nil {
+		t.Error("iregex on sqlite should fail")
+	}
+	if _, _, err := ToSQL(regex, 42); err == nil {
+		t.Error("unknown dialect should fail")
+	}
+}
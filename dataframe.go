@@ -6,6 +6,8 @@ import (
 	"github.com/hunknownz/godas/internal"
 	"github.com/hunknownz/godas/internal/elements"
 	sbool "github.com/hunknownz/godas/internal/elements_bool"
+	sdatetime "github.com/hunknownz/godas/internal/elements_datetime"
+	sduration "github.com/hunknownz/godas/internal/elements_duration"
 	ec "github.com/hunknownz/godas/internal/elements_composite"
 	"github.com/hunknownz/godas/types"
 	"io"
@@ -21,6 +23,9 @@ import (
 	sobject "github.com/hunknownz/godas/internal/elements_object"
 	sstring "github.com/hunknownz/godas/internal/elements_string"
 	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type DataFrame struct {
@@ -28,6 +33,8 @@ type DataFrame struct {
 
 	sourceType reflect.Type
 	Err error
+
+	parallelism int
 }
 
 func (df *DataFrame) NumRow() int {
@@ -89,7 +96,7 @@ func (df *DataFrame) checkSeriesLengths(ses ...*Series) (err error) {
 }
 
 func (df *DataFrame) Subset(index index.IndexInt) (newDataFrame *DataFrame, err error) {
-	newElements, err := df.data.Subset(index)
+	newElements, err := df.data.SubsetParallel(index, df.workers())
 	if err != nil {
 		err = fmt.Errorf("subset dataframe error: %w", err)
 		return
@@ -173,7 +180,7 @@ func (df *DataFrame) GetSeriesByColumn(column string) (newSeries *Series, err er
 
 func (df *DataFrame) Copy() (newDataFrame *DataFrame) {
 	data := df.data
-	newDataFrame, _ = newFromArrays(data.NArray...)
+	newDataFrame, _ = newFromArraysParallel(df.workers(), data.NArray...)
 	return
 }
 
@@ -261,6 +268,16 @@ func (df *DataFrame) appendMapLikeObject(values map[string]interface{}) {
 			for _, v := range val {
 				array.Append(false, v)
 			}
+		case []time.Time:
+			val := value.([]time.Time)
+			for _, v := range val {
+				array.Append(false, v)
+			}
+		case []time.Duration:
+			val := value.([]time.Duration)
+			for _, v := range val {
+				array.Append(false, v)
+			}
 		}
 	}
 	return
@@ -303,6 +320,16 @@ func (df *DataFrame) checkAppendMapLikeRecords(values map[string]interface{}) (e
 				err = errors.New(fmt.Sprintf("can't append object value to %s series", typ))
 				return
 			}
+		case []time.Time:
+			if typ != types.TypeDatetime {
+				err = errors.New(fmt.Sprintf("can't append time value to %s series", typ))
+				return
+			}
+		case []time.Duration:
+			if typ != types.TypeDuration {
+				err = errors.New(fmt.Sprintf("can't append duration value to %s series", typ))
+				return
+			}
 		default:
 			valueType := reflect.TypeOf(value).Kind().String()
 			err = errors.New(fmt.Sprintf("type %s is not supported in this dataframe", valueType))
@@ -346,55 +373,118 @@ func (df *DataFrame) Append(copy bool, records ...interface{}) (newDataFrame *Da
 	return
 }
 
-func (df *DataFrame) evaluateCondition(expr condition.ExprAST) index.IndexBool {
-	var l, r index.IndexBool
+// evaluateCondition evaluates expr in three-valued logic, comparisons with
+// missing elements following the missing policy missing. unknown marks the
+// rows whose result is unknown, which are false in ixs.
+func (df *DataFrame) evaluateCondition(expr condition.ExprAST, missing int) (ixs, unknown index.IndexBool) {
 	switch expr.(type) {
 	case condition.BinaryExprAST:
 		ast := expr.(condition.BinaryExprAST)
-		l = df.evaluateCondition(ast.Lhs)
-		r = df.evaluateCondition(ast.Rhs)
+		ixs, unknown = df.evaluateCondition(ast.Lhs, missing)
+		r, rUnknown := df.evaluateCondition(ast.Rhs, missing)
+		if df.Err != nil {
+			return
+		}
 		switch ast.Op {
 		case "&&":
-			l.And(r)
-			return l
+			ixs.AndKleene(unknown, r, rUnknown)
 		case "||":
-			l.Or(r)
-			return l
+			ixs.OrKleene(unknown, r, rUnknown)
 		}
+		return
 	case condition.ValueExprAST:
 		cond := expr.(condition.ValueExprAST).Value
 		if cond.Cond != nil {
 			nextExpr := cond.Cond.Prepare()
-			return df.evaluateCondition(nextExpr)
+			ixs, unknown = df.evaluateCondition(nextExpr, cond.Cond.InheritMissing(missing))
+			if cond.IsNot && df.Err == nil {
+				ixs.NotKleene(unknown)
+			}
+			return
 		}
 		cmp := cond.CompItem
-		seriesVal, err := df.GetSeriesByColumn(cmp.Column)
-		if err != nil {
-			df.Err = err
-			return l
+		var err error
+		if cmp.Comparator == condition.ComparatorRowFunc {
+			ixs, err = df.evaluateRowFunc(cmp)
+			if err != nil {
+				df.Err = err
+				return
+			}
+			unknown = make(index.IndexBool, len(ixs))
+			if cond.IsNot {
+				ixs.Not()
+			}
+			return
+		}
+		arrayI, ok := df.data.FieldArraysMap[cmp.Column]
+		if !ok {
+			df.Err = errors.New(fmt.Sprintf("column name %q not found", cmp.Column))
+			return
+		}
+		seriesVal := &Series{
+			array: df.data.NArray[arrayI],
 		}
 
-		newCondition := NewDataFrameCondition()
-		newCondition.Or(cmp.Comparator, cmp.Value)
-		ixs, err := seriesVal.IsCondition(newCondition)
+		if ref, ok := cmp.Value.(condition.ColumnRef); ok {
+			ixs, unknown, err = df.compareColumnRef(seriesVal, cmp.Comparator, ref, missing)
+		} else {
+			newCondition := NewDataFrameCondition()
+			newCondition.Or(cmp.Comparator, cmp.Value).SetMissing(missing)
+			ixs, unknown, err = seriesVal.array.IsConditionKleene(newCondition, df.workers())
+		}
 		if err != nil {
 			df.Err = err
-			return ixs
+			return
+		}
+		if cond.IsNot {
+			ixs.NotKleene(unknown)
 		}
-		return ixs
+		return
 	}
 
 	data := df.data
-	l = make(index.IndexBool, data.NArray[0].Len())
-	for i := 0; i < len(l); i++ {
-		l[i] = true
+	ixs = make(index.IndexBool, data.NArray[0].Len())
+	unknown = make(index.IndexBool, len(ixs))
+	for i := 0; i < len(ixs); i++ {
+		ixs[i] = true
 	}
-	return l
+	return
 }
 
+func (df *DataFrame) evaluateRowFunc(cmp *condition.CompItem) (ixs index.IndexBool, err error) {
+	f, ok := cmp.Value.(condition.RowFunc)
+	if !ok || f == nil {
+		err = errors.New("row func value must be a non-nil condition.RowFunc")
+		return
+	}
+	data := df.data
+	isNaN := make([][]bool, len(data.NArray))
+	for i, array := range data.NArray {
+		isNaN[i] = array.Elements.IsNaN()
+	}
+	rowNum := df.NumRow()
+	ixs = make(index.IndexBool, rowNum)
+	for rowI := 0; rowI < rowNum; rowI++ {
+		row := make(condition.Row, len(data.NArray))
+		for i, array := range data.NArray {
+			element, e := array.Elements.Location(rowI)
+			if e != nil {
+				err = e
+				return
+			}
+			element.IsNaN = isNaN[i][rowI]
+			row[array.FieldName] = element
+		}
+		ixs[rowI] = f(row)
+	}
+	return
+}
+
+// IsCondition evaluates cond for every row. Only the rows for which cond
+// is true, not unknown, are selected.
 func (df *DataFrame) IsCondition(cond *condition.Condition) (ixs index.IndexBool, err error) {
 	expr := cond.Prepare()
-	ixs = df.evaluateCondition(expr)
+	ixs, _ = df.evaluateCondition(expr, cond.Missing())
 	if df.Err != nil {
 		err = fmt.Errorf("filter error: %w", df.Err)
 		return
@@ -422,6 +512,22 @@ func (df *DataFrame) Filter(cond *condition.Condition) (newDataFrame *DataFrame,
 	return
 }
 
+// Query filters the rows matching a query string such as
+// "age >= 18 && country in ('DE', 'FR')". See condition.ParseQuery for the
+// syntax.
+func (df *DataFrame) Query(query string) (newDataFrame *DataFrame, err error) {
+	cond, err := condition.ParseQuery(query)
+	if err != nil {
+		err = fmt.Errorf("query error: %w", err)
+		return
+	}
+	newDataFrame, err = df.Filter(cond)
+	if err != nil {
+		err = fmt.Errorf("query error: %w", err)
+	}
+	return
+}
+
 func (df *DataFrame) Sort(inplace bool, sortKeys ...SortKey) (newDataFrame *DataFrame, err error) {
 	if inplace {
 		newDataFrame = df
@@ -429,9 +535,10 @@ func (df *DataFrame) Sort(inplace bool, sortKeys ...SortKey) (newDataFrame *Data
 		newDataFrame = df.Copy()
 	}
 
-	sorter, err := newDataframeSorter(df, sortKeys...)
+	sorter, err := newDataframeSorter(newDataFrame, sortKeys...)
 	if err != nil {
 		err = fmt.Errorf("sort error: %w", err)
+		return
 	}
 	sorter.Sort()
 	return
@@ -513,6 +620,12 @@ func NewDataFrameCondition() *condition.Condition {
 }
 
 func newFromArrays(arrays ...*ec.Array) (df *DataFrame, err error) {
+	return newFromArraysParallel(1, arrays...)
+}
+
+// newFromArraysParallel is newFromArrays copying the arrays on up to
+// workers goroutines.
+func newFromArraysParallel(workers int, arrays ...*ec.Array) (df *DataFrame, err error) {
 	if arrays == nil || len(arrays) == 0 {
 		df = &DataFrame{
 			data: newEmptyData(),
@@ -527,9 +640,10 @@ func newFromArrays(arrays ...*ec.Array) (df *DataFrame, err error) {
 	}
 
 	nArray := make([]*ec.Array, len(arrays))
-	for i, array := range arrays {
-		nArray[i] = array.Copy()
-	}
+	internal.ParallelEach(workers, len(arrays), func(i int) error {
+		nArray[i] = arrays[i].Copy()
+		return nil
+	})
 
 	df = &DataFrame{
 		data: &ec.ElementsComposite{
@@ -597,11 +711,51 @@ func NewFromSeries(ses ...*Series) (df *DataFrame, err error) {
 	return
 }
 
+// structFieldNames turns the field names of the arrays into unique exported
+// Go identifiers, so columns such as "margin" or "unit price" can be used as
+// fields of the generated row struct.
+func structFieldNames(arrays []*ec.Array) (fieldNames []string) {
+	fieldNames = make([]string, len(arrays))
+	used := make(map[string]bool)
+	for i, array := range arrays {
+		var builder strings.Builder
+		for j, r := range array.FieldName {
+			switch {
+			case unicode.IsLetter(r) || r == '_':
+				builder.WriteRune(r)
+			case unicode.IsDigit(r):
+				if j == 0 {
+					builder.WriteRune('C')
+				}
+				builder.WriteRune(r)
+			default:
+				builder.WriteRune('_')
+			}
+		}
+		name := builder.String()
+		first, size := utf8.DecodeRuneInString(name)
+		switch {
+		case unicode.IsUpper(first):
+		case unicode.IsLower(first):
+			name = string(unicode.ToUpper(first)) + name[size:]
+		default:
+			name = "C" + name
+		}
+		if used[name] {
+			name = name + "_" + strconv.Itoa(i)
+		}
+		used[name] = true
+		fieldNames[i] = name
+	}
+	return
+}
+
 func generateAnonymousStructType(df *DataFrame) reflect.Type {
 	columnNum := df.NumColumn()
 	structFields := make([]reflect.StructField, columnNum)
 
 	data := df.data
+	fieldNames := structFieldNames(data.NArray)
 	for i:=0; i < columnNum; i++ {
 		array := data.NArray[i]
 		seType := array.Type()
@@ -609,47 +763,81 @@ func generateAnonymousStructType(df *DataFrame) reflect.Type {
 		switch seType {
 		case types.TypeInt:
 			structField = reflect.StructField{
-				Name:      array.FieldName,
+				Name:      fieldNames[i],
 				Type:      reflect.TypeOf(int64(0)),
 			}
 		case types.TypeBool:
 			structField = reflect.StructField{
-				Name:      array.FieldName,
+				Name:      fieldNames[i],
 				Type:      reflect.TypeOf(true),
 			}
 		case types.TypeFloat:
 			structField = reflect.StructField{
-				Name:      array.FieldName,
+				Name:      fieldNames[i],
 				Type:      reflect.TypeOf(float64(0)),
 			}
 		case types.TypeString:
 			structField = reflect.StructField{
-				Name:      array.FieldName,
+				Name:      fieldNames[i],
 				Type:      reflect.TypeOf(""),
 			}
+		case types.TypeDatetime:
+			structField = reflect.StructField{
+				Name:      fieldNames[i],
+				Type:      reflect.TypeOf(time.Time{}),
+			}
+		case types.TypeDuration:
+			structField = reflect.StructField{
+				Name:      fieldNames[i],
+				Type:      reflect.TypeOf(time.Duration(0)),
+			}
+		case types.TypeObject:
+			structField = reflect.StructField{
+				Name:      fieldNames[i],
+				Type:      reflect.TypeOf((*interface{})(nil)).Elem(),
+			}
 		}
 		structFields[i] = structField
 	}
 	return reflect.StructOf(structFields)
 }
 
+// generateTypeArrays reads the field fieldIndex of the structs in
+// valuesValue. Time fields are read as datetimes, in the location of the
+// first one, and duration fields as durations. Nil pointers to ints,
+// floats, strings, bools, times or durations are read as nulls.
 func generateTypeArrays(valuesValue reflect.Value, fieldIndex int, valueType string, fieldName string, ptrFlag bool) (newArray *ec.Array) {
 	seriesLen := valuesValue.Len()
 
+	nullable := false
+	switch valueType {
+	case "*float32", "*float64", "*int8", "*int16", "*int", "*int32", "*int64", "*string", "*bool", "*time.Time", "*time.Duration":
+		nullable = true
+		valueType = valueType[1:]
+	}
+	isNull := make([]bool, seriesLen)
+	fieldValue := func(i int) (val reflect.Value) {
+		if ptrFlag {
+			val = valuesValue.Index(i).Elem().Field(fieldIndex)
+		} else {
+			val = valuesValue.Index(i).Field(fieldIndex)
+		}
+		if nullable {
+			isNull[i] = val.IsNil()
+			val = val.Elem()
+		}
+		return
+	}
+
 	switch valueType {
 	case "float", "float32", "float64":
 		elements := make([]float64, seriesLen)
 		for i := 0; i < seriesLen; i++ {
-			var val reflect.Value
-			if ptrFlag {
-				val = valuesValue.Index(i).Elem().Field(fieldIndex)
-			} else {
-				val = valuesValue.Index(i).Field(fieldIndex)
+			if val := fieldValue(i); val.IsValid() {
+				elements[i] = val.Float()
 			}
-
-			elements[i] = val.Float()
 		}
-		newElements := sfloat.NewElementsFloat64(elements)
+		newElements := sfloat.NewNullableElementsFloat64(elements, isNull)
 		newArray = &ec.Array{
 			FieldName: fieldName,
 			Elements:  newElements,
@@ -657,16 +845,11 @@ func generateTypeArrays(valuesValue reflect.Value, fieldIndex int, valueType str
 	case "int8", "int16", "int", "int32", "int64":
 		elements := make([]int64, seriesLen)
 		for i := 0; i < seriesLen; i++ {
-			var val reflect.Value
-			if ptrFlag {
-				val = valuesValue.Index(i).Elem().Field(fieldIndex)
-			} else {
-				val = valuesValue.Index(i).Field(fieldIndex)
+			if val := fieldValue(i); val.IsValid() {
+				elements[i] = val.Int()
 			}
-
-			elements[i] = val.Int()
 		}
-		newElements := sint.NewElementsInt64(elements)
+		newElements := sint.NewNullableElementsInt64(elements, isNull)
 		newArray = &ec.Array{
 			FieldName: fieldName,
 			Elements:  newElements,
@@ -674,16 +857,11 @@ func generateTypeArrays(valuesValue reflect.Value, fieldIndex int, valueType str
 	case "string":
 		elements := make([]string, seriesLen)
 		for i := 0; i < seriesLen; i++ {
-			var val reflect.Value
-			if ptrFlag {
-				val = valuesValue.Index(i).Elem().Field(fieldIndex)
-			} else {
-				val = valuesValue.Index(i).Field(fieldIndex)
+			if val := fieldValue(i); val.IsValid() {
+				elements[i] = val.String()
 			}
-
-			elements[i] = val.String()
 		}
-		newElements := sstring.NewElementsString(elements)
+		newElements := sstring.NewNullableElementsString(elements, isNull)
 		newArray = &ec.Array{
 			FieldName: fieldName,
 			Elements:  newElements,
@@ -691,16 +869,40 @@ func generateTypeArrays(valuesValue reflect.Value, fieldIndex int, valueType str
 	case "bool":
 		elements := make([]bool, seriesLen)
 		for i := 0; i < seriesLen; i++ {
-			var val reflect.Value
-			if ptrFlag {
-				val = valuesValue.Index(i).Elem().Field(fieldIndex)
-			} else {
-				val = valuesValue.Index(i).Field(fieldIndex)
+			if val := fieldValue(i); val.IsValid() {
+				elements[i] = val.Bool()
+			}
+		}
+		newElements := sbool.NewNullableElementsBool(elements, isNull)
+		newArray = &ec.Array{
+			FieldName: fieldName,
+			Elements:  newElements,
+		}
+	case "time.Time":
+		elements := make([]int64, seriesLen)
+		var location *time.Location
+		for i := 0; i < seriesLen; i++ {
+			if val := fieldValue(i); val.IsValid() {
+				t := val.Interface().(time.Time)
+				if location == nil {
+					location = t.Location()
+				}
+				elements[i] = t.UnixNano()
+			}
+		}
+		newElements := sdatetime.NewElementsDatetime(elements, isNull, location)
+		newArray = &ec.Array{
+			FieldName: fieldName,
+			Elements:  newElements,
+		}
+	case "time.Duration":
+		elements := make([]int64, seriesLen)
+		for i := 0; i < seriesLen; i++ {
+			if val := fieldValue(i); val.IsValid() {
+				elements[i] = val.Int()
 			}
-
-			elements[i] = val.Bool()
 		}
-		newElements := sbool.NewElementsBool(elements)
+		newElements := sduration.NewElementsDuration(elements, isNull)
 		newArray = &ec.Array{
 			FieldName: fieldName,
 			Elements:  newElements,
@@ -708,14 +910,7 @@ func generateTypeArrays(valuesValue reflect.Value, fieldIndex int, valueType str
 	default:
 		elements := make([]interface{}, seriesLen)
 		for i := 0; i < seriesLen; i++ {
-			var val reflect.Value
-			if ptrFlag {
-				val = valuesValue.Index(i).Elem().Field(fieldIndex)
-			} else {
-				val = valuesValue.Index(i).Field(fieldIndex)
-			}
-
-			elements[i] = val.Interface()
+			elements[i] = fieldValue(i).Interface()
 		}
 		newElements := sobject.NewElementsObject(elements)
 		newArray = &ec.Array{
@@ -794,19 +989,32 @@ func NewFromStructs(values interface{}) (df *DataFrame, err error) {
 	return
 }
 
+// IndexStruct returns row rowLabel as a struct. Null elements are left nil
+// in pointer fields and zero in other ones.
 func (df *DataFrame) IndexStruct(rowLabel int) (rowStruct interface{}, err error) {
 	val := reflect.New(df.sourceType).Elem()
 	columnNum := df.NumColumn()
 
 	data := df.data
+	fieldNames := structFieldNames(data.NArray)
 	for i := 0; i < columnNum; i++ {
 		array := data.NArray[i]
 		lValue := val.FieldByName(array.FieldName)
+		if !lValue.IsValid() {
+			lValue = val.FieldByName(fieldNames[i])
+		}
 		elem, e := array.At(rowLabel)
 		if e != nil {
 			err = fmt.Errorf("series at %d error: %w", rowLabel, e)
 			return
 		}
+		if lValue.Kind() == reflect.Ptr && elem.Type != types.TypeObject {
+			if elem.IsNull {
+				continue
+			}
+			lValue.Set(reflect.New(lValue.Type().Elem()))
+			lValue = lValue.Elem()
+		}
 		switch lValue.Type().Kind() {
 		case reflect.String:
 			rValue := elem.MustString()
@@ -814,13 +1022,13 @@ func (df *DataFrame) IndexStruct(rowLabel int) (rowStruct interface{}, err error
 		case reflect.Bool:
 			rValue := elem.MustBool()
 			lValue.SetBool(rValue)
-		case reflect.Int:
-			rValue := elem.MustInt()
-			lValue.SetInt(rValue)
-		case reflect.Int64:
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			rValue := elem.MustInt()
+			if elem.Type == types.TypeDuration {
+				rValue = int64(elem.MustDuration())
+			}
 			lValue.SetInt(rValue)
-		case reflect.Float64:
+		case reflect.Float32, reflect.Float64:
 			rValue := elem.MustFloat()
 			lValue.SetFloat(rValue)
 		default:
@@ -842,7 +1050,22 @@ func (df *DataFrame) ToStructs() (structs []interface{}) {
 	return
 }
 
-func NewFromCSV(filepathOrBufferstr interface{}) (df *DataFrame, err error) {
+// CSVOptions configures NewFromCSV. DatetimeLayouts maps the columns to
+// read as datetimes to their time layouts, parsed in Location, UTC when
+// nil. DurationColumns are read as Go or ISO 8601 durations. The other
+// columns are read as strings.
+type CSVOptions struct {
+	DatetimeLayouts map[string]string
+	Location        *time.Location
+	DurationColumns []string
+}
+
+func NewFromCSV(filepathOrBufferstr interface{}, opts ...CSVOptions) (df *DataFrame, err error) {
+	var options CSVOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
 	var	reader io.Reader
 	switch filepathOrBufferstr.(type) {
 	case string:
@@ -873,6 +1096,32 @@ func NewFromCSV(filepathOrBufferstr interface{}) (df *DataFrame, err error) {
 		newData.FieldArraysMap[header] = columnI
 		newData.NArray[columnI], _ = ec.NewArray(dataMap[header], header)
 	}
+	for column, layout := range options.DatetimeLayouts {
+		columnI, ok := newData.FieldArraysMap[column]
+		if !ok {
+			err = errors.New(fmt.Sprintf("read csv error: column name %q not found", column))
+			return
+		}
+		newElements, e := parseDatetimes(dataMap[column].([]*string), layout, options.Location)
+		if e != nil {
+			err = fmt.Errorf("read csv column %q error: %w", column, e)
+			return
+		}
+		newData.NArray[columnI].Elements = newElements
+	}
+	for _, column := range options.DurationColumns {
+		columnI, ok := newData.FieldArraysMap[column]
+		if !ok {
+			err = errors.New(fmt.Sprintf("read csv error: column name %q not found", column))
+			return
+		}
+		newElements, e := parseDurations(dataMap[column].([]*string))
+		if e != nil {
+			err = fmt.Errorf("read csv column %q error: %w", column, e)
+			return
+		}
+		newData.NArray[columnI].Elements = newElements
+	}
 
 	df.sourceType = generateAnonymousStructType(df)
 
@@ -0,0 +1,29 @@
+package godas
+
+import (
+	"fmt"
+	"github.com/hunknownz/godas/types"
+)
+
+// AsType converts the columns named in columnTypes to their types, the
+// other columns are kept unchanged.
+func (df *DataFrame) AsType(columnTypes map[string]types.Type, opts AsTypeOptions) (newDataFrame *DataFrame, err error) {
+	for column := range columnTypes {
+		if _, err = df.getOriginSeriesByColumn(column); err != nil {
+			err = fmt.Errorf("as type error: %w", err)
+			return
+		}
+	}
+
+	newDataFrame, err = df.transformColumns(func(se *Series) (*Series, error) {
+		typ, ok := columnTypes[se.array.FieldName]
+		if !ok {
+			return se, nil
+		}
+		return se.AsType(typ, opts)
+	})
+	if err != nil {
+		err = fmt.Errorf("as type error: %w", err)
+	}
+	return
+}
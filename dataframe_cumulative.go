@@ -0,0 +1,139 @@
+package godas
+
+import (
+	"fmt"
+	"github.com/hunknownz/godas/internal"
+	ec "github.com/hunknownz/godas/internal/elements_composite"
+	"github.com/hunknownz/godas/types"
+)
+
+type seriesGroupsFunc func(se *Series, groups [][]int) (*Series, error)
+
+// transformNumeric applies f to every int and float column that is not one
+// of the by columns. Rows are grouped by the by columns first, the other
+// columns are kept unchanged.
+func (df *DataFrame) transformNumeric(f seriesGroupsFunc, by ...string) (newDataFrame *DataFrame, err error) {
+	groups, err := df.groupRows(by...)
+	if err != nil {
+		return
+	}
+
+	data := df.data
+	arrays := make([]*ec.Array, len(data.NArray))
+	err = internal.ParallelEach(df.workers(), len(data.NArray), func(i int) error {
+		array := data.NArray[i]
+		typ := array.Type()
+		isKey, _ := internal.ArrayContain(by, array.FieldName)
+		if isKey || (typ != types.TypeInt && typ != types.TypeFloat) {
+			arrays[i] = array
+			return nil
+		}
+
+		se := &Series{
+			array: array,
+		}
+		newSe, e := f(se, groups)
+		if e != nil {
+			return fmt.Errorf("column %q error: %w", array.FieldName, e)
+		}
+		arrays[i] = newSe.array
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	newDataFrame, err = newFromArrays(arrays...)
+	return
+}
+
+// CumSum returns the cumulative sum of every numeric column, computed
+// within the groups formed by the by columns.
+func (df *DataFrame) CumSum(by ...string) (newDataFrame *DataFrame, err error) {
+	f := func(se *Series, groups [][]int) (*Series, error) {
+		return se.cumulate(cumulativeSum, groups)
+	}
+	newDataFrame, err = df.transformNumeric(f, by...)
+	if err != nil {
+		err = fmt.Errorf("cumsum error: %w", err)
+	}
+	return
+}
+
+// CumProd returns the cumulative product of every numeric column, computed
+// within the groups formed by the by columns.
+func (df *DataFrame) CumProd(by ...string) (newDataFrame *DataFrame, err error) {
+	f := func(se *Series, groups [][]int) (*Series, error) {
+		return se.cumulate(cumulativeProd, groups)
+	}
+	newDataFrame, err = df.transformNumeric(f, by...)
+	if err != nil {
+		err = fmt.Errorf("cumprod error: %w", err)
+	}
+	return
+}
+
+// CumMin returns the running minimum of every numeric column, computed
+// within the groups formed by the by columns.
+func (df *DataFrame) CumMin(by ...string) (newDataFrame *DataFrame, err error) {
+	f := func(se *Series, groups [][]int) (*Series, error) {
+		return se.cumulate(cumulativeMin, groups)
+	}
+	newDataFrame, err = df.transformNumeric(f, by...)
+	if err != nil {
+		err = fmt.Errorf("cummin error: %w", err)
+	}
+	return
+}
+
+// CumMax returns the running maximum of every numeric column, computed
+// within the groups formed by the by columns.
+func (df *DataFrame) CumMax(by ...string) (newDataFrame *DataFrame, err error) {
+	f := func(se *Series, groups [][]int) (*Series, error) {
+		return se.cumulate(cumulativeMax, groups)
+	}
+	newDataFrame, err = df.transformNumeric(f, by...)
+	if err != nil {
+		err = fmt.Errorf("cummax error: %w", err)
+	}
+	return
+}
+
+// Shift shifts every numeric column by periods rows within the groups
+// formed by the by columns.
+func (df *DataFrame) Shift(periods int, by ...string) (newDataFrame *DataFrame, err error) {
+	f := func(se *Series, groups [][]int) (*Series, error) {
+		return se.shift(periods, groups)
+	}
+	newDataFrame, err = df.transformNumeric(f, by...)
+	if err != nil {
+		err = fmt.Errorf("shift error: %w", err)
+	}
+	return
+}
+
+// Diff returns the difference with the row periods rows before for every
+// numeric column, within the groups formed by the by columns.
+func (df *DataFrame) Diff(periods int, by ...string) (newDataFrame *DataFrame, err error) {
+	f := func(se *Series, groups [][]int) (*Series, error) {
+		return se.diff(periods, groups)
+	}
+	newDataFrame, err = df.transformNumeric(f, by...)
+	if err != nil {
+		err = fmt.Errorf("diff error: %w", err)
+	}
+	return
+}
+
+// PctChange returns the fractional change with the row periods rows before
+// for every numeric column, within the groups formed by the by columns.
+func (df *DataFrame) PctChange(periods int, by ...string) (newDataFrame *DataFrame, err error) {
+	f := func(se *Series, groups [][]int) (*Series, error) {
+		return se.pctChange(periods, groups)
+	}
+	newDataFrame, err = df.transformNumeric(f, by...)
+	if err != nil {
+		err = fmt.Errorf("pct change error: %w", err)
+	}
+	return
+}
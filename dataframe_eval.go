@@ -0,0 +1,408 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/condition"
+	"github.com/hunknownz/godas/expression"
+	"github.com/hunknownz/godas/index"
+	sbool "github.com/hunknownz/godas/internal/elements_bool"
+	sfloat "github.com/hunknownz/godas/internal/elements_float"
+	sint "github.com/hunknownz/godas/internal/elements_int"
+	"math"
+)
+
+// Eval evaluates an assignment such as "margin = (revenue - cost) / revenue"
+// and returns a new dataframe holding the result in the target column. An
+// existing column with the same name is replaced.
+//
+// Expressions support column references, numeric, string and bool literals,
+// + - * / %, comparisons, && || !, parentheses and the functions abs(x),
+// log(x), round(x[, digits]) and if(cond, a, b).
+func (df *DataFrame) Eval(expr string) (newDataFrame *DataFrame, err error) {
+	target, node, err := expression.ParseAssignment(expr)
+	if err != nil {
+		err = fmt.Errorf("eval error: %w", err)
+		return
+	}
+
+	se, err := df.evaluateExpression(node)
+	if err != nil {
+		err = fmt.Errorf("eval error: %w", err)
+		return
+	}
+	se = se.Copy()
+	se.array.FieldName = target
+
+	arrayI, ok := df.data.FieldArraysMap[target]
+	if !ok {
+		newDataFrame, err = df.AssignSeries(false, se)
+		return
+	}
+	newDataFrame = df.Copy()
+	newDataFrame.data.NArray[arrayI] = se.array
+	newDataFrame.sourceType = generateAnonymousStructType(newDataFrame)
+	return
+}
+
+func newConstantSeries(value interface{}, seLen int) (se *Series, err error) {
+	var values interface{}
+	switch value.(type) {
+	case int64:
+		constant := make([]int64, seLen)
+		for i := range constant {
+			constant[i] = value.(int64)
+		}
+		values = constant
+	case float64:
+		constant := make([]float64, seLen)
+		for i := range constant {
+			constant[i] = value.(float64)
+		}
+		values = constant
+	case string:
+		constant := make([]string, seLen)
+		for i := range constant {
+			constant[i] = value.(string)
+		}
+		values = constant
+	case bool:
+		constant := make([]bool, seLen)
+		for i := range constant {
+			constant[i] = value.(bool)
+		}
+		values = constant
+	}
+	se, err = NewSeries(values, "")
+	return
+}
+
+func newBoolSeries(ixs index.IndexBool) *Series {
+	se, _ := NewSeries([]bool(ixs), "")
+	return se
+}
+
+func (se *Series) boolValues() (values index.IndexBool, err error) {
+	elements, ok := se.array.Elements.(sbool.ElementsBool)
+	if !ok {
+		err = errors.New(fmt.Sprintf("expected bool values, found %s", se.Type()))
+		return
+	}
+	seLen := elements.Len()
+	values = make(index.IndexBool, seLen)
+	for i := 0; i < seLen; i++ {
+		element, _ := elements.Location(i)
+		values[i] = element.MustBool()
+	}
+	return
+}
+
+func (df *DataFrame) evaluateExpression(node expression.Node) (se *Series, err error) {
+	switch node.(type) {
+	case expression.NumberNode:
+		se, err = newConstantSeries(node.(expression.NumberNode).Value, df.NumRow())
+	case expression.StringNode:
+		se, err = newConstantSeries(node.(expression.StringNode).Value, df.NumRow())
+	case expression.BoolNode:
+		se, err = newConstantSeries(node.(expression.BoolNode).Value, df.NumRow())
+	case expression.ColumnNode:
+		column := node.(expression.ColumnNode)
+		arrayI, ok := df.data.FieldArraysMap[column.Name]
+		if !ok {
+			err = errors.New(fmt.Sprintf("column name %q not found at position %d", column.Name, column.Pos))
+			return
+		}
+		se = &Series{
+			array: df.data.NArray[arrayI],
+		}
+	case expression.UnaryNode:
+		se, err = df.evaluateUnary(node.(expression.UnaryNode))
+	case expression.BinaryNode:
+		se, err = df.evaluateBinary(node.(expression.BinaryNode))
+	case expression.CallNode:
+		se, err = df.evaluateCall(node.(expression.CallNode))
+	default:
+		err = errors.New(fmt.Sprintf("unknown expression %s", node))
+	}
+	return
+}
+
+func (df *DataFrame) evaluateUnary(node expression.UnaryNode) (se *Series, err error) {
+	operand, err := df.evaluateExpression(node.Operand)
+	if err != nil {
+		return
+	}
+	switch node.Op {
+	case "-":
+		se, err = operand.Mul(-1)
+	case "!":
+		values, e := operand.boolValues()
+		if e != nil {
+			err = e
+			return
+		}
+		for i := range values {
+			values[i] = !values[i]
+		}
+		se = newBoolSeries(values)
+	}
+	return
+}
+
+func (df *DataFrame) evaluateBinary(node expression.BinaryNode) (se *Series, err error) {
+	lhs, err := df.evaluateExpression(node.Lhs)
+	if err != nil {
+		return
+	}
+	rhs, err := df.evaluateExpression(node.Rhs)
+	if err != nil {
+		return
+	}
+
+	var ixs index.IndexBool
+	switch node.Op {
+	case "+":
+		se, err = lhs.Add(rhs)
+	case "-":
+		se, err = lhs.Sub(rhs)
+	case "*":
+		se, err = lhs.Mul(rhs)
+	case "/":
+		se, err = lhs.Div(rhs)
+	case "%":
+		se, err = lhs.Mod(rhs)
+	case "=", "==":
+		ixs, err = lhs.Eq(rhs)
+	case "!=":
+		ixs, err = lhs.Ne(rhs)
+	case "<":
+		ixs, err = lhs.Lt(rhs)
+	case "<=":
+		ixs, err = lhs.Le(rhs)
+	case ">":
+		ixs, err = lhs.Gt(rhs)
+	case ">=":
+		ixs, err = lhs.Ge(rhs)
+	case "&&", "||":
+		l, e := lhs.boolValues()
+		if e != nil {
+			err = e
+			return
+		}
+		r, e := rhs.boolValues()
+		if e != nil {
+			err = e
+			return
+		}
+		if node.Op == "&&" {
+			l.And(r)
+		} else {
+			l.Or(r)
+		}
+		ixs = l
+	default:
+		err = errors.New(fmt.Sprintf("unknown operator %q", node.Op))
+	}
+	if err != nil {
+		err = fmt.Errorf("operator %q at position %d: %w", node.Op, node.Pos, err)
+		return
+	}
+	if ixs != nil {
+		se = newBoolSeries(ixs)
+	}
+	return
+}
+
+func (df *DataFrame) evaluateCall(node expression.CallNode) (se *Series, err error) {
+	args := make([]*Series, len(node.Args))
+	for i, arg := range node.Args {
+		args[i], err = df.evaluateExpression(arg)
+		if err != nil {
+			return
+		}
+	}
+
+	switch node.Func {
+	case "abs":
+		if len(args) != 1 {
+			break
+		}
+		se, err = args[0].mapNumeric(func(value int64) int64 {
+			if value < 0 {
+				return -value
+			}
+			return value
+		}, math.Abs)
+		return
+	case "log":
+		if len(args) != 1 {
+			break
+		}
+		se, err = args[0].mapNumeric(nil, math.Log)
+		return
+	case "round":
+		if len(args) != 1 && len(args) != 2 {
+			break
+		}
+		digits := int64(0)
+		if len(args) == 2 {
+			number, ok := node.Args[1].(expression.NumberNode)
+			digit, isInt := number.Value.(int64)
+			if !ok || !isInt {
+				err = errors.New(fmt.Sprintf("round digits at position %d must be an int literal", node.Args[1].Position()))
+				return
+			}
+			digits = digit
+		}
+		scale := math.Pow(10, float64(digits))
+		se, err = args[0].mapNumeric(func(value int64) int64 {
+			return value
+		}, func(value float64) float64 {
+			return math.Round(value*scale) / scale
+		})
+		return
+	case "if":
+		if len(args) != 3 {
+			break
+		}
+		se, err = ifSeries(args[0], args[1], args[2])
+		return
+	default:
+		err = errors.New(fmt.Sprintf("unknown function %q at position %d", node.Func, node.Pos))
+		return
+	}
+	err = errors.New(fmt.Sprintf("wrong number of arguments for %s at position %d", node.Func, node.Pos))
+	return
+}
+
+// mapNumeric applies intFunc to the elements of an int series and floatFunc
+// to the elements of a float series. Null and NaN elements are kept. A nil
+// intFunc converts int series to float and applies floatFunc.
+func (se *Series) mapNumeric(intFunc func(int64) int64, floatFunc func(float64) float64) (newSeries *Series, err error) {
+	isNaN, isNull := se.IsNaN(), se.IsNull()
+	switch se.array.Elements.(type) {
+	case sint.ElementsInt64:
+		values := se.array.Elements.(sint.ElementsInt64).Values()
+		if intFunc == nil {
+			floatValues, _, _ := se.floatValues()
+			floatElements := sfloat.NewNullableElementsFloat64(floatValues, isNull)
+			newSeries, err = se.newDerivedSeries(floatElements).mapNumeric(nil, floatFunc)
+			return
+		}
+		result := make([]int64, len(values))
+		for i, value := range values {
+			if isNaN[i] {
+				continue
+			}
+			result[i] = intFunc(value)
+		}
+		newSeries = se.newDerivedSeries(sint.NewNullableElementsInt64(result, isNull))
+	case sfloat.ElementsFloat64:
+		values := se.array.Elements.(sfloat.ElementsFloat64).Values()
+		result := make([]float64, len(values))
+		for i, value := range values {
+			if isNaN[i] {
+				result[i] = math.NaN()
+				continue
+			}
+			result[i] = floatFunc(value)
+		}
+		newSeries = se.newDerivedSeries(sfloat.NewNullableElementsFloat64(result, isNull))
+	default:
+		err = errors.New(fmt.Sprintf("type %s is not numeric", se.Type()))
+	}
+	return
+}
+
+// ifSeries picks the element of a where cond is true and the element of b
+// otherwise. The result is int when both a and b are int, float otherwise.
+func ifSeries(cond, a, b *Series) (se *Series, err error) {
+	conds, err := cond.boolValues()
+	if err != nil {
+		return
+	}
+	lhs, err := newOperand(a)
+	if err != nil {
+		return
+	}
+	rhs, err := newOperand(b)
+	if err != nil {
+		return
+	}
+	if lhs.typ == operandString || rhs.typ == operandString {
+		err = errors.New("if doesn't support string values")
+		return
+	}
+
+	if lhs.typ == operandInt && rhs.typ == operandInt {
+		result := make([]int64, len(conds))
+		isNull := make([]bool, len(conds))
+		for i, c := range conds {
+			op := rhs
+			if c {
+				op = lhs
+			}
+			result[i], isNull[i] = op.intAt(i), op.nullAt(i)
+		}
+		se = a.newDerivedSeries(sint.NewNullableElementsInt64(result, isNull))
+		return
+	}
+
+	result := make([]float64, len(conds))
+	isNull := make([]bool, len(conds))
+	for i, c := range conds {
+		op := rhs
+		if c {
+			op = lhs
+		}
+		isNull[i] = op.nullAt(i)
+		if op.nanAt(i) {
+			result[i] = math.NaN()
+			continue
+		}
+		result[i] = op.floatAt(i)
+	}
+	se = a.newDerivedSeries(sfloat.NewNullableElementsFloat64(result, isNull))
+	return
+}
+
+// compareColumnRef compares se element-wise with the expression of ref,
+// evaluated over the columns of df. Comparisons where either side is
+// missing follow the missing policy missing.
+func (df *DataFrame) compareColumnRef(se *Series, comparator string, ref condition.ColumnRef, missing int) (ixs, unknown index.IndexBool, err error) {
+	rhs, err := df.evaluateExpression(ref.Expr)
+	if err != nil {
+		err = fmt.Errorf("column %s: %w", se.array.FieldName, err)
+		return
+	}
+	switch comparator {
+	case condition.ComparatorGT:
+		ixs, err = se.Gt(rhs)
+	case condition.ComparatorGTE:
+		ixs, err = se.Ge(rhs)
+	case condition.ComparatorLT:
+		ixs, err = se.Lt(rhs)
+	case condition.ComparatorLTE:
+		ixs, err = se.Le(rhs)
+	case condition.ComparatorEq:
+		ixs, err = se.Eq(rhs)
+	case condition.ComparatorNotEq:
+		ixs, err = se.Ne(rhs)
+	default:
+		err = errors.New(fmt.Sprintf("comparator %s is not supported against %s", comparator, ref))
+	}
+	if err != nil {
+		err = fmt.Errorf("column %s: %w", se.array.FieldName, err)
+		return
+	}
+
+	missingResult, missingUnknown := condition.CompareMissing(missing)
+	lhsNaN, rhsNaN := se.IsNaN(), rhs.IsNaN()
+	unknown = make(index.IndexBool, len(ixs))
+	for i := range ixs {
+		if lhsNaN[i] || rhsNaN[i%len(rhsNaN)] {
+			ixs[i], unknown[i] = missingResult, missingUnknown
+		}
+	}
+	return
+}
@@ -0,0 +1,54 @@
+package godas
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDataFrameEval(t *testing.T) {
+	revenue, _ := NewSeries([]int{100, 200, 0}, "revenue")
+	cost, _ := NewSeries([]float64{80, 150, 10}, "cost")
+	df, err := NewFromSeries(revenue, cost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := df.Eval("margin = (revenue - cost) / revenue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.NumColumn() != 3 || df.NumColumn() != 2 {
+		t.Fatalf("unexpected columns %d %d", result.NumColumn(), df.NumColumn())
+	}
+	margin, _ := result.At(0, "margin")
+	if !almostEqual(margin.MustFloat(), 0.2) {
+		t.Errorf("margin: got %v, want 0.2", margin.MustFloat())
+	}
+	margin, _ = result.At(2, "margin")
+	if !math.IsInf(margin.MustFloat(), -1) {
+		t.Errorf("margin with zero revenue: got %v, want -Inf", margin.MustFloat())
+	}
+
+	result, err = df.Eval("revenue = if(revenue > 150 && cost > 0, revenue % 7, abs(-1))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int64{1, 4, 1} {
+		got, _ := result.At(i, "revenue")
+		if got.MustInt() != want {
+			t.Errorf("if at %d: got %v, want %v", i, got.MustInt(), want)
+		}
+	}
+
+	rowStruct, err := result.IndexStruct(1)
+	if err != nil || rowStruct == nil {
+		t.Errorf("index struct: %v", err)
+	}
+
+	if _, err := df.Eval("x = unknown + 1"); err == nil {
+		t.Error("unknown column should fail")
+	}
+	if _, err := df.Eval("x = round(cost, 1, 2)"); err == nil {
+		t.Error("wrong argument count should fail")
+	}
+}
@@ -0,0 +1,67 @@
+package godas
+
+import (
+	"fmt"
+	"strings"
+)
+
+// groupRows partitions the row positions of the dataframe by the values of
+// the given columns. Groups are kept in order of first appearance and rows
+// keep their original order inside a group. Without columns all rows form
+// a single group.
+func (df *DataFrame) groupRows(columns ...string) (groups [][]int, err error) {
+	groups, _, err = df.groupRowsByKey(columns...)
+	return
+}
+
+// groupRowsByKey is groupRows also returning the key of every group. Null
+// values form their own groups, apart from the zero values they hold.
+func (df *DataFrame) groupRowsByKey(columns ...string) (groups [][]int, keys []string, err error) {
+	rowNum := df.NumRow()
+	if len(columns) == 0 {
+		rows := make([]int, rowNum)
+		for i := 0; i < rowNum; i++ {
+			rows[i] = i
+		}
+		groups, keys = [][]int{rows}, []string{""}
+		return
+	}
+
+	data := df.data
+	keyColumns := make([]int, len(columns))
+	for i, column := range columns {
+		arrayI, ok := data.FieldArraysMap[column]
+		if !ok {
+			err = fmt.Errorf("group column name %q not found", column)
+			return
+		}
+		keyColumns[i] = arrayI
+	}
+
+	groupsMap := make(map[string]int)
+	keyParts := make([]string, len(keyColumns))
+	for row := 0; row < rowNum; row++ {
+		for i, arrayI := range keyColumns {
+			value, e := data.NArray[arrayI].At(row)
+			if e != nil {
+				err = fmt.Errorf("group rows error: %w", e)
+				return
+			}
+			if value.IsNull {
+				keyParts[i] = "n"
+				continue
+			}
+			keyParts[i] = "v" + fmt.Sprintf("%v", value.Value)
+		}
+		key := strings.Join(keyParts, "\x00")
+		groupI, ok := groupsMap[key]
+		if !ok {
+			groupI = len(groups)
+			groupsMap[key] = groupI
+			groups = append(groups, make([]int, 0))
+			keys = append(keys, key)
+		}
+		groups[groupI] = append(groups[groupI], row)
+	}
+	return
+}
@@ -0,0 +1,396 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/index"
+	"github.com/hunknownz/godas/internal"
+	"github.com/hunknownz/godas/internal/elements"
+	ec "github.com/hunknownz/godas/internal/elements_composite"
+	sdatetime "github.com/hunknownz/godas/internal/elements_datetime"
+	sduration "github.com/hunknownz/godas/internal/elements_duration"
+	sfloat "github.com/hunknownz/godas/internal/elements_float"
+	sint "github.com/hunknownz/godas/internal/elements_int"
+	sstring "github.com/hunknownz/godas/internal/elements_string"
+	"github.com/hunknownz/godas/types"
+	"math"
+	"reflect"
+	"time"
+)
+
+const (
+	AsOfBackward = "backward"
+	AsOfForward  = "forward"
+	AsOfNearest  = "nearest"
+)
+
+const mergeSuffix = "_right"
+
+// MergeAsOfOptions configures MergeAsOf. By are columns of both frames that
+// must match exactly. Direction is AsOfBackward, the default, AsOfForward
+// or AsOfNearest, nearest preferring the backward row on ties. Tolerance,
+// when not nil, is the largest key distance of a match: a time.Duration
+// for datetime and duration keys, an int or a float for numeric keys.
+type MergeAsOfOptions struct {
+	By        []string
+	Direction string
+	Tolerance interface{}
+}
+
+// takeNulls returns the null mask of the elements at rows, a row -1 being
+// null.
+func takeNulls(validity elements.Validity, rows []int) (isNull []bool) {
+	isNull = make([]bool, len(rows))
+	for i, row := range rows {
+		isNull[i] = row < 0 || !validity.IsValid(row)
+	}
+	return
+}
+
+// takeInt64s returns the values at rows, 0 for a row -1.
+func takeInt64s(values []int64, rows []int) (taken []int64) {
+	taken = make([]int64, len(rows))
+	for i, row := range rows {
+		if row >= 0 {
+			taken[i] = values[row]
+		}
+	}
+	return
+}
+
+// takeRows returns the elements of se at rows, a row -1 being null.
+// Rows may repeat, unlike with Subset. Int, float, string, datetime and
+// duration elements are gathered from their values and validity, so a
+// float NaN stays a NaN, the other types element by element.
+func (se *Series) takeRows(rows []int) (newSeries *Series, err error) {
+	var newElements elements.Elements
+	switch typed := se.array.Elements.(type) {
+	case sint.ElementsInt64:
+		newElements = sint.NewNullableElementsInt64(takeInt64s(typed.Values(), rows), takeNulls(typed.Validity(), rows))
+	case sdatetime.ElementsDatetime:
+		newElements = sdatetime.NewElementsDatetime(takeInt64s(typed.Values(), rows), takeNulls(typed.Validity(), rows), typed.TimeLocation())
+	case sduration.ElementsDuration:
+		newElements = sduration.NewElementsDuration(takeInt64s(typed.Values(), rows), takeNulls(typed.Validity(), rows))
+	case sfloat.ElementsFloat64:
+		values := typed.Values()
+		taken := make([]float64, len(rows))
+		for i, row := range rows {
+			if row >= 0 {
+				taken[i] = values[row]
+			}
+		}
+		newElements = sfloat.NewNullableElementsFloat64(taken, takeNulls(typed.Validity(), rows))
+	case sstring.ElementsString:
+		values := typed.Values()
+		taken := make([]string, len(rows))
+		for i, row := range rows {
+			if row >= 0 {
+				taken[i] = values[row]
+			}
+		}
+		newElements = sstring.NewNullableElementsString(taken, takeNulls(typed.Validity(), rows))
+	default:
+		values := make([]interface{}, len(rows))
+		isNull := se.IsNull()
+		for i, row := range rows {
+			if row < 0 || isNull[row] {
+				continue
+			}
+			element, _ := se.array.Elements.Location(row)
+			values[i] = element.Value
+		}
+		empty, e := se.array.Elements.Subset(index.IndexInt{})
+		if e != nil {
+			err = e
+			return
+		}
+		newElements, err = empty.Append(false, values...)
+		if err != nil {
+			return
+		}
+	}
+	newSeries = se.newDerivedSeries(newElements)
+	return
+}
+
+// asOfKeys holds the keys of the on column of a frame, as ints for int,
+// datetime and duration columns and as floats for float columns.
+type asOfKeys struct {
+	typ    types.Type
+	ints   []int64
+	floats []float64
+	isNaN  []bool
+}
+
+func newAsOfKeys(se *Series) (keys asOfKeys, err error) {
+	keys.typ, keys.isNaN = se.Type(), se.IsNaN()
+	switch keys.typ {
+	case types.TypeInt, types.TypeDatetime, types.TypeDuration:
+		keys.ints, _ = se.intValues()
+	case types.TypeFloat:
+		keys.floats, _, err = se.floatValues()
+	default:
+		err = errors.New(fmt.Sprintf("key type %s is not numeric or datetime", keys.typ))
+	}
+	return
+}
+
+func (keys asOfKeys) float(i int) float64 {
+	if keys.floats != nil {
+		return keys.floats[i]
+	}
+	return float64(keys.ints[i])
+}
+
+// compareKeys returns the sign of left key l minus right key r and their
+// distance.
+func compareKeys(left, right asOfKeys, l, r int) (sign int, distance float64) {
+	if left.ints != nil && right.ints != nil {
+		a, b := left.ints[l], right.ints[r]
+		switch {
+		case a < b:
+			sign = -1
+		case a > b:
+			sign = 1
+		}
+		return sign, math.Abs(float64(a) - float64(b))
+	}
+	a, b := left.float(l), right.float(r)
+	switch {
+	case a < b:
+		sign = -1
+	case a > b:
+		sign = 1
+	}
+	return sign, math.Abs(a - b)
+}
+
+// sortedRows returns the rows whose key isn't missing, checking that their
+// keys are sorted in ascending order.
+func (keys asOfKeys) sortedRows(rows []int) (sorted []int, err error) {
+	sorted = make([]int, 0, len(rows))
+	for _, row := range rows {
+		if keys.isNaN[row] {
+			continue
+		}
+		if len(sorted) > 0 {
+			if sign, _ := compareKeys(keys, keys, sorted[len(sorted)-1], row); sign > 0 {
+				err = errors.New(fmt.Sprintf("keys must be sorted in ascending order, row %d is out of order", row))
+				return
+			}
+		}
+		sorted = append(sorted, row)
+	}
+	return
+}
+
+func asOfTolerance(typ types.Type, tolerance interface{}) (limit float64, err error) {
+	limit = math.Inf(1)
+	if tolerance == nil {
+		return
+	}
+	switch tolerance.(type) {
+	case time.Duration:
+		if typ == types.TypeDatetime || typ == types.TypeDuration {
+			limit = float64(tolerance.(time.Duration))
+		}
+	case int, int8, int16, int32, int64:
+		if typ == types.TypeInt || typ == types.TypeFloat {
+			limit = float64(reflect.ValueOf(tolerance).Int())
+		}
+	case float32, float64:
+		if typ == types.TypeInt || typ == types.TypeFloat {
+			limit = reflect.ValueOf(tolerance).Float()
+		}
+	}
+	if math.IsInf(limit, 1) || limit < 0 {
+		err = errors.New(fmt.Sprintf("invalid tolerance %v for %s keys", tolerance, typ))
+	}
+	return
+}
+
+// byGroups groups the rows of df by the by columns. keys maps the key of
+// each group to the group, leaving out the groups with a null by column,
+// which match nothing.
+func (df *DataFrame) byGroups(by []string) (groups [][]int, keys map[string]int, err error) {
+	groups, groupKeys, err := df.groupRowsByKey(by...)
+	if err != nil {
+		return
+	}
+	keys = make(map[string]int, len(groups))
+	for groupI, rows := range groups {
+		if len(rows) == 0 {
+			continue
+		}
+		hasNull := false
+		for _, column := range by {
+			value, _ := df.data.NArray[df.data.FieldArraysMap[column]].At(rows[0])
+			hasNull = hasNull || value.IsNull
+		}
+		if !hasNull {
+			keys[groupKeys[groupI]] = groupI
+		}
+	}
+	return
+}
+
+// MergeAsOf matches every row of df with the row of other whose on key is
+// the nearest in the direction of opts, among the rows with the same by
+// columns. Both frames must be sorted by on in ascending order, they're
+// merged in linear time. The result holds the columns of df followed by
+// the other columns of other, null for the rows without a match. Other
+// columns whose name is already taken get the suffix "_right", followed by
+// a counter from 2 when that name is taken too.
+func (df *DataFrame) MergeAsOf(other *DataFrame, on string, opts MergeAsOfOptions) (newDataFrame *DataFrame, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("merge as of error: %w", err)
+		}
+	}()
+	direction := opts.Direction
+	if direction == "" {
+		direction = AsOfBackward
+	}
+	if direction != AsOfBackward && direction != AsOfForward && direction != AsOfNearest {
+		err = errors.New(fmt.Sprintf("unknown direction %q", direction))
+		return
+	}
+	leftSe, err := df.getOriginSeriesByColumn(on)
+	if err != nil {
+		return
+	}
+	rightSe, err := other.getOriginSeriesByColumn(on)
+	if err != nil {
+		return
+	}
+	left, err := newAsOfKeys(leftSe)
+	if err != nil {
+		return
+	}
+	right, err := newAsOfKeys(rightSe)
+	if err != nil {
+		return
+	}
+	numeric := func(typ types.Type) bool {
+		return typ == types.TypeInt || typ == types.TypeFloat
+	}
+	if left.typ != right.typ && !(numeric(left.typ) && numeric(right.typ)) {
+		err = errors.New(fmt.Sprintf("can't match %s keys with %s keys", left.typ, right.typ))
+		return
+	}
+	limit, err := asOfTolerance(left.typ, opts.Tolerance)
+	if err != nil {
+		return
+	}
+
+	leftGroups, leftKeys, err := df.byGroups(opts.By)
+	if err != nil {
+		return
+	}
+	rightGroups, rightKeys, err := other.byGroups(opts.By)
+	if err != nil {
+		return
+	}
+
+	matches := make([]int, df.NumRow())
+	for i := range matches {
+		matches[i] = -1
+	}
+	for key, leftGroupI := range leftKeys {
+		leftRows, e := left.sortedRows(leftGroups[leftGroupI])
+		if e != nil {
+			err = e
+			return
+		}
+		rightGroupI, ok := rightKeys[key]
+		if !ok {
+			continue
+		}
+		rightRows, e := right.sortedRows(rightGroups[rightGroupI])
+		if e != nil {
+			err = e
+			return
+		}
+
+		// j is the first right row whose key isn't below the left key and k
+		// the first one above it. Both only move forward.
+		j, k := 0, 0
+		for _, l := range leftRows {
+			for j < len(rightRows) {
+				if sign, _ := compareKeys(left, right, l, rightRows[j]); sign <= 0 {
+					break
+				}
+				j++
+			}
+			if k < j {
+				k = j
+			}
+			for k < len(rightRows) {
+				if sign, _ := compareKeys(left, right, l, rightRows[k]); sign < 0 {
+					break
+				}
+				k++
+			}
+			backward := k - 1
+			match, bestDistance := -1, math.Inf(1)
+			if direction != AsOfForward && backward >= 0 {
+				_, distance := compareKeys(left, right, l, rightRows[backward])
+				match, bestDistance = rightRows[backward], distance
+			}
+			if direction != AsOfBackward && j < len(rightRows) {
+				_, distance := compareKeys(left, right, l, rightRows[j])
+				if distance < bestDistance {
+					match, bestDistance = rightRows[j], distance
+				}
+			}
+			if match >= 0 && bestDistance <= limit {
+				matches[l] = match
+			}
+		}
+	}
+
+	arrays := append([]*ec.Array{}, df.data.NArray...)
+	var rightArrays []*ec.Array
+	for _, array := range other.data.NArray {
+		isKey, _ := internal.ArrayContain(opts.By, array.FieldName)
+		if isKey || array.FieldName == on {
+			continue
+		}
+		rightArrays = append(rightArrays, array)
+	}
+	taken := make([]*ec.Array, len(rightArrays))
+	err = internal.ParallelEach(df.workers(), len(rightArrays), func(i int) error {
+		se := &Series{
+			array: rightArrays[i],
+		}
+		newSe, e := se.takeRows(matches)
+		if e != nil {
+			return fmt.Errorf("column %q error: %w", rightArrays[i].FieldName, e)
+		}
+		taken[i] = newSe.array
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	used := make(map[string]bool, len(arrays)+len(taken))
+	for _, array := range arrays {
+		used[array.FieldName] = true
+	}
+	for _, array := range taken {
+		used[array.FieldName] = true
+	}
+	for _, array := range taken {
+		if _, ok := df.data.FieldArraysMap[array.FieldName]; ok {
+			name := array.FieldName + mergeSuffix
+			for n := 2; used[name]; n++ {
+				name = fmt.Sprintf("%s%s%d", array.FieldName, mergeSuffix, n)
+			}
+			array.FieldName, used[name] = name, true
+		}
+		arrays = append(arrays, array)
+	}
+
+	newDataFrame, err = newFromArrays(arrays...)
+	return
+}
@@ -0,0 +1,183 @@
+package godas
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDataFrameMergeAsOf(t *testing.T) {
+	type trade struct {
+		Time   time.Time
+		Symbol string
+		Price  float64
+	}
+	type quote struct {
+		Time   time.Time
+		Symbol string
+		Bid    float64
+		Price  float64
+	}
+	base := time.Date(2020, time.January, 1, 9, 30, 0, 0, time.UTC)
+	at := func(ms int) time.Time {
+		return base.Add(time.Duration(ms) * time.Millisecond)
+	}
+	trades, err := NewFromStructs([]trade{
+		{at(23), "MSFT", 51.95},
+		{at(38), "MSFT", 51.95},
+		{at(48), "GOOG", 720.77},
+		{at(48), "GOOG", 720.92},
+		{at(48), "AAPL", 98.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	quotes, err := NewFromStructs([]quote{
+		{at(23), "GOOG", 720.50, 720.93},
+		{at(23), "MSFT", 51.95, 51.96},
+		{at(30), "MSFT", 51.97, 51.98},
+		{at(41), "MSFT", 51.99, 52.00},
+		{at(48), "GOOG", 720.50, 720.93},
+		{at(49), "AAPL", 97.99, 98.01},
+		{at(72), "GOOG", 720.50, 720.88},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := trades.MergeAsOf(quotes, "Time", MergeAsOfOptions{By: []string{"Symbol"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := merged.data.Fields; len(got) != 5 || got[3] != "Bid" || got[4] != "Price_right" {
+		t.Errorf("columns: got %v", got)
+	}
+	se, _ := merged.GetSeriesByColumn("Bid")
+	if got := se.array.Elements.String(); got != "[51.95 51.97 720.5 720.5 null]" {
+		t.Errorf("backward: got %s", got)
+	}
+
+	tolerated, _ := trades.MergeAsOf(quotes, "Time", MergeAsOfOptions{By: []string{"Symbol"}, Tolerance: 2 * time.Millisecond})
+	se, _ = tolerated.GetSeriesByColumn("Bid")
+	if got := se.array.Elements.String(); got != "[51.95 null 720.5 720.5 null]" {
+		t.Errorf("tolerance: got %s", got)
+	}
+
+	forward, _ := trades.MergeAsOf(quotes, "Time", MergeAsOfOptions{By: []string{"Symbol"}, Direction: AsOfForward})
+	se, _ = forward.GetSeriesByColumn("Bid")
+	if got := se.array.Elements.String(); got != "[51.95 51.99 720.5 720.5 97.99]" {
+		t.Errorf("forward: got %s", got)
+	}
+
+	nearest, _ := trades.MergeAsOf(quotes, "Time", MergeAsOfOptions{Direction: AsOfNearest})
+	se, _ = nearest.GetSeriesByColumn("Symbol_right")
+	if got := se.array.Elements.String(); got != "[MSFT MSFT GOOG GOOG GOOG]" {
+		t.Errorf("nearest: got %s", got)
+	}
+
+	if _, err = trades.MergeAsOf(quotes, "Time", MergeAsOfOptions{Tolerance: 2}); err == nil {
+		t.Error("expected a tolerance type error")
+	}
+	if _, err = trades.MergeAsOf(quotes, "Symbol", MergeAsOfOptions{}); err == nil {
+		t.Error("expected a key type error")
+	}
+	if _, err = trades.MergeAsOf(quotes, "Time", MergeAsOfOptions{Direction: "sideways"}); err == nil {
+		t.Error("expected a direction error")
+	}
+	if _, err = quotes.MergeAsOf(trades, "Price", MergeAsOfOptions{}); err == nil {
+		t.Error("expected an unsorted keys error")
+	}
+}
+
+func TestDataFrameMergeAsOfNumeric(t *testing.T) {
+	type left struct {
+		Key   int
+		Value string
+	}
+	type right struct {
+		Key   float64
+		Other *int
+	}
+	one, two := 1, 2
+	lefts, _ := NewFromStructs([]left{{1, "a"}, {5, "b"}, {10, "c"}})
+	rights, _ := NewFromStructs([]right{{2, &one}, {3, nil}, {6, &two}})
+
+	merged, err := lefts.MergeAsOf(rights, "Key", MergeAsOfOptions{Direction: AsOfNearest, Tolerance: 2.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ := merged.GetSeriesByColumn("Other")
+	if got := se.array.Elements.String(); got != "[1 2 null]" {
+		t.Errorf("nearest: got %s", got)
+	}
+}
+
+func TestDataFrameMergeAsOfNaN(t *testing.T) {
+	lefts, _ := NewFromStructs([]struct {
+		Key int
+	}{{1}, {2}, {3}})
+	nan := math.NaN()
+	one := 1.0
+	rights, _ := NewFromStructs([]struct {
+		Key   int
+		Value *float64
+	}{{1, &nan}, {2, nil}, {3, &one}})
+
+	merged, err := lefts.MergeAsOf(rights, "Key", MergeAsOfOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ := merged.GetSeriesByColumn("Value")
+	if got := se.array.Elements.String(); got != "[NaN null 1]" {
+		t.Errorf("got %s, want the NaN kept apart from the null", got)
+	}
+	if isNull := se.IsNull(); isNull[0] || !isNull[1] {
+		t.Errorf("null mask: got %v", isNull)
+	}
+}
+
+func TestDataFrameMergeAsOfNullBy(t *testing.T) {
+	zero := 0
+	lefts, _ := NewFromStructs([]struct {
+		Key int
+		G   *int
+	}{{1, nil}, {2, &zero}})
+	rights, _ := NewFromStructs([]struct {
+		Key   int
+		G     *int
+		Value string
+	}{{0, nil, "a"}, {1, &zero, "b"}})
+
+	merged, err := lefts.MergeAsOf(rights, "Key", MergeAsOfOptions{By: []string{"G"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ := merged.GetSeriesByColumn("Value")
+	if got := se.array.Elements.String(); got != "[null b]" {
+		t.Errorf("null by: got %s, want nulls to match nothing", got)
+	}
+}
+
+func TestDataFrameMergeAsOfSuffix(t *testing.T) {
+	lefts, _ := NewFromStructs([]struct {
+		K int
+		X string
+	}{{1, "l"}})
+	rights, _ := NewFromStructs([]struct {
+		K       int
+		X       string
+		X_right string
+	}{{1, "r", "rr"}})
+
+	merged, err := lefts.MergeAsOf(rights, "K", MergeAsOfOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := merged.data.Fields; len(got) != 4 || got[2] != "X_right2" || got[3] != "X_right" {
+		t.Fatalf("columns: got %v", got)
+	}
+	se, _ := merged.GetSeriesByColumn("X_right2")
+	if got := se.array.Elements.String(); got != "[r]" {
+		t.Errorf("renamed column: got %s", got)
+	}
+}
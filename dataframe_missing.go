@@ -0,0 +1,144 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/index"
+	"github.com/hunknownz/godas/internal"
+	ec "github.com/hunknownz/godas/internal/elements_composite"
+)
+
+const (
+	DropAny = "any"
+	DropAll = "all"
+)
+
+// DropNA returns the rows that aren't missing in the subset columns, all
+// columns when subset is empty. DropAny drops a row when any of the columns
+// is null or NaN, DropAll only when all of them are.
+func (df *DataFrame) DropNA(how string, subset ...string) (newDataFrame *DataFrame, err error) {
+	if how != DropAny && how != DropAll {
+		err = errors.New(fmt.Sprintf("drop na error: unknown how %q", how))
+		return
+	}
+	data := df.data
+	if len(subset) == 0 {
+		subset = data.Fields
+	}
+	masks := make([][]bool, len(subset))
+	for i, column := range subset {
+		se, e := df.getOriginSeriesByColumn(column)
+		if e != nil {
+			err = fmt.Errorf("drop na error: %w", e)
+			return
+		}
+		masks[i] = se.IsNaN()
+	}
+
+	nRow := df.NumRow()
+	idx := make(index.IndexInt, 0, nRow)
+	for rowI := 0; rowI < nRow; rowI++ {
+		drop := how == DropAll && len(masks) > 0
+		for _, isNaN := range masks {
+			if how == DropAny && isNaN[rowI] {
+				drop = true
+				break
+			}
+			if how == DropAll && !isNaN[rowI] {
+				drop = false
+				break
+			}
+		}
+		if !drop {
+			idx = append(idx, uint32(rowI))
+		}
+	}
+
+	newDataFrame, err = df.Subset(idx)
+	if err != nil {
+		err = fmt.Errorf("drop na error: %w", err)
+	}
+	return
+}
+
+// transformColumns applies f to every column for which f is not nil, the
+// other columns are kept unchanged.
+func (df *DataFrame) transformColumns(f func(se *Series) (*Series, error)) (newDataFrame *DataFrame, err error) {
+	data := df.data
+	arrays := make([]*ec.Array, len(data.NArray))
+	err = internal.ParallelEach(df.workers(), len(data.NArray), func(i int) error {
+		array := data.NArray[i]
+		se := &Series{
+			array: array,
+		}
+		newSe, e := f(se)
+		if e != nil {
+			return fmt.Errorf("column %q error: %w", array.FieldName, e)
+		}
+		arrays[i] = newSe.array
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	newDataFrame, err = newFromArrays(arrays...)
+	return
+}
+
+// FillNA replaces the null and NaN elements with value. A scalar value fills
+// every column of a type it can fill and skips the others. A
+// map[string]interface{} value fills each named column with its own value,
+// an unknown column or a value of the wrong type is an error.
+func (df *DataFrame) FillNA(value interface{}) (newDataFrame *DataFrame, err error) {
+	values, isMap := value.(map[string]interface{})
+	if isMap {
+		for column, columnValue := range values {
+			se, e := df.getOriginSeriesByColumn(column)
+			if e != nil {
+				err = fmt.Errorf("fill na error: %w", e)
+				return
+			}
+			if _, ok := fillValue(se.Type(), columnValue); !ok {
+				err = errors.New(fmt.Sprintf("fill na error: can't fill column %q of type %s with %v", column, se.Type(), columnValue))
+				return
+			}
+		}
+	}
+
+	newDataFrame, err = df.transformColumns(func(se *Series) (*Series, error) {
+		fill := value
+		if isMap {
+			columnValue, ok := values[se.array.FieldName]
+			if !ok {
+				return se, nil
+			}
+			fill = columnValue
+		} else if _, ok := fillValue(se.Type(), fill); !ok {
+			return se, nil
+		}
+		return se.FillNA(fill)
+	})
+	if err != nil {
+		err = fmt.Errorf("fill na error: %w", err)
+	}
+	return
+}
+
+// FFill forward fills the null and NaN elements of every column.
+func (df *DataFrame) FFill() (newDataFrame *DataFrame, err error) {
+	newDataFrame, err = df.transformColumns((*Series).FFill)
+	if err != nil {
+		err = fmt.Errorf("ffill error: %w", err)
+	}
+	return
+}
+
+// BFill backward fills the null and NaN elements of every column.
+func (df *DataFrame) BFill() (newDataFrame *DataFrame, err error) {
+	newDataFrame, err = df.transformColumns((*Series).BFill)
+	if err != nil {
+		err = fmt.Errorf("bfill error: %w", err)
+	}
+	return
+}
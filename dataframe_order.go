@@ -56,10 +56,12 @@ func newDataframeSorter(df *DataFrame, sortKeys ...SortKey) (sorter *dataframeSo
 				err = fmt.Errorf("sort error: %w", e)
 				return
 			}
+			// The less funcs must read the arrays the sorter swaps.
+			se.array = df.data.NArray[df.data.FieldArraysMap[sortKeys[i].Column]]
 
 			seType := se.Type()
 			switch seType {
-			case types.TypeInt:
+			case types.TypeInt, types.TypeDatetime, types.TypeDuration:
 				f := func(a, b int64) bool {
 					return a < b
 				}
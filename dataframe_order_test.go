@@ -0,0 +1,44 @@
+package godas
+
+import (
+	"testing"
+)
+
+func TestDataFrameSortMultiKey(t *testing.T) {
+	type row struct {
+		Group int
+		Name  string
+		Score float64
+	}
+	df, err := NewFromStructs([]row{
+		{2, "b", 1.5},
+		{1, "a", 3},
+		{2, "d", 0.5},
+		{1, "c", 2},
+		{2, "a", 4},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sorted, err := df.Sort(false, SortKey{Column: "Group", Ascending: true}, SortKey{Column: "Name", Ascending: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]string{
+		"Group": "[1 1 2 2 2]",
+		"Name":  "[c a d b a]",
+		"Score": "[2 3 0.5 1.5 4]",
+	}
+	for column, want := range cases {
+		se, _ := sorted.GetSeriesByColumn(column)
+		if got := se.array.Elements.String(); got != want {
+			t.Errorf("sorted %s: got %s, want %s", column, got, want)
+		}
+	}
+
+	se, _ := df.GetSeriesByColumn("Name")
+	if got := se.array.Elements.String(); got != "[b a d c a]" {
+		t.Errorf("original: got %s, want it unchanged", got)
+	}
+}
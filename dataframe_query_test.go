@@ -0,0 +1,98 @@
+package godas
+
+import (
+	"errors"
+	"github.com/hunknownz/godas/condition"
+	"github.com/hunknownz/godas/expression"
+	"testing"
+)
+
+func TestDataFrameQuery(t *testing.T) {
+	type person struct {
+		Name    string
+		Age     int
+		Country string
+	}
+	people := []person{
+		{"Anna", 17, "DE"},
+		{"Ben", 34, "FR"},
+		{"Carl", 51, "IT"},
+		{"Dora", 22, "DE"},
+	}
+	df, err := NewFromStructs(people)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newDataFrame, err := df.Query("Age >= 18 && Country in ('DE', 'FR')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newDataFrame.NumRow() != 2 {
+		t.Errorf("got %d rows, want 2", newDataFrame.NumRow())
+	}
+
+	newDataFrame, _ = df.Query("!(Country = 'DE') || Name startswith 'A'")
+	if newDataFrame.NumRow() != 3 {
+		t.Errorf("got %d rows, want 3", newDataFrame.NumRow())
+	}
+
+	_, err = df.Query("Age >= && Country = 'DE'")
+	var parseErr *expression.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Pos != 7 {
+		t.Errorf("expected parse error at position 7, got %v", err)
+	}
+}
+
+func TestDataFrameColumnComparison(t *testing.T) {
+	type project struct {
+		Name   string
+		Actual float64
+		Budget int
+		Start  int
+		End    int
+	}
+	projects := []project{
+		{"a", 80, 100, 1, 3},
+		{"b", 95, 100, 5, 4},
+		{"c", 10, 20, 2, 2},
+	}
+	df, err := NewFromStructs(projects)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cond := NewDataFrameCondition()
+	cond.And(">", condition.Col("Start"), "End")
+	newDataFrame, err := df.Filter(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newDataFrame.NumRow() != 1 {
+		t.Errorf("col: got %d rows, want 1", newDataFrame.NumRow())
+	}
+
+	newDataFrame, err = df.Query("Actual < Budget * 0.9 || End != Start")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newDataFrame.NumRow() != 3 {
+		t.Errorf("expression: got %d rows, want 3", newDataFrame.NumRow())
+	}
+
+	newDataFrame, _ = df.Query("Actual < Budget * 0.9 && End >= Start")
+	if newDataFrame.NumRow() != 2 {
+		t.Errorf("expression: got %d rows, want 2", newDataFrame.NumRow())
+	}
+
+	if _, err = df.Query("Name > Budget"); err == nil {
+		t.Error("comparing string with number column should fail")
+	}
+
+	se, _ := df.GetSeriesByColumn("End")
+	seCond := NewSeriesCondition()
+	seCond.And(">", condition.Col("Start"))
+	if _, err = se.IsCondition(seCond); err == nil {
+		t.Error("column reference in a series condition should fail")
+	}
+}
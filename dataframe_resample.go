@@ -0,0 +1,375 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/internal"
+	ec "github.com/hunknownz/godas/internal/elements_composite"
+	sdatetime "github.com/hunknownz/godas/internal/elements_datetime"
+	sduration "github.com/hunknownz/godas/internal/elements_duration"
+	sfloat "github.com/hunknownz/godas/internal/elements_float"
+	sint "github.com/hunknownz/godas/internal/elements_int"
+	"github.com/hunknownz/godas/types"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AggCount  = "count"
+	AggSum    = "sum"
+	AggMean   = "mean"
+	AggMedian = "median"
+	AggMin    = "min"
+	AggMax    = "max"
+	AggFirst  = "first"
+	AggLast   = "last"
+)
+
+const FillForward = "ffill"
+
+// maxResampleWindows bounds the number of windows Resample allocates, so
+// that a frequency far below the span of the datetimes fails instead of
+// running out of memory.
+const maxResampleWindows = 1 << 24
+
+// ResampleOptions configures Resample. The windows start at Origin plus
+// multiples of the frequency, Origin being the midnight before the first
+// datetime when zero. Fill fills the windows without rows, which happen
+// when upsampling: FillForward carries the last non-null aggregate
+// forward, InterpolateLinear and InterpolateNearest interpolate the int,
+// float and duration columns. Empty windows are null when Fill is empty
+// or can't fill the column, and windows whose rows are all null stay null.
+// The sum of an empty window is 0 when Fill is empty and filled otherwise.
+type ResampleOptions struct {
+	Origin time.Time
+	Fill   string
+}
+
+// parseFrequency parses a resampling frequency, a duration string or a
+// number of days or weeks such as "1d" or "2w".
+func parseFrequency(frequency string) (d time.Duration, err error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if !strings.HasSuffix(frequency, suffix) {
+			continue
+		}
+		n, e := strconv.Atoi(strings.TrimSuffix(frequency, suffix))
+		if e != nil {
+			err = errors.New(fmt.Sprintf("invalid frequency %q", frequency))
+			return
+		}
+		d = time.Duration(n) * unit
+		return
+	}
+	d, err = internal.ParseDuration(frequency)
+	if err != nil {
+		err = errors.New(fmt.Sprintf("invalid frequency %q", frequency))
+	}
+	return
+}
+
+// floorDiv divides a by b rounding towards negative infinity.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// Resample buckets the rows into fixed windows of frequency, such as "5m",
+// "1h" or "1d", of the datetime column timeColumn and aggregates the
+// columns named in aggs with AggCount, AggSum, AggMean, AggMedian, AggMin,
+// AggMax, AggFirst or AggLast, timeColumn itself excepted. The result holds
+// one row per window, from the first to the last, labeled by the window
+// start. Rows with a null datetime are dropped.
+func (df *DataFrame) Resample(timeColumn, frequency string, aggs map[string]string, opts ResampleOptions) (newDataFrame *DataFrame, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("resample error: %w", err)
+		}
+	}()
+	freq, err := parseFrequency(frequency)
+	if err != nil {
+		return
+	}
+	if freq <= 0 {
+		err = errors.New(fmt.Sprintf("frequency %q must be positive", frequency))
+		return
+	}
+	timeSeries, err := df.getOriginSeriesByColumn(timeColumn)
+	if err != nil {
+		return
+	}
+	timeElements, ok := timeSeries.array.Elements.(sdatetime.ElementsDatetime)
+	if !ok {
+		err = errors.New(fmt.Sprintf("column %q of type %s is not datetime", timeColumn, timeSeries.Type()))
+		return
+	}
+	switch opts.Fill {
+	case "", FillForward, InterpolateLinear, InterpolateNearest:
+	default:
+		err = errors.New(fmt.Sprintf("unknown fill %q", opts.Fill))
+		return
+	}
+	for column := range aggs {
+		if column == timeColumn {
+			err = errors.New(fmt.Sprintf("can't aggregate the time column %q", timeColumn))
+			return
+		}
+		if _, ok := df.data.FieldArraysMap[column]; !ok {
+			err = errors.New(fmt.Sprintf("column name %q not found", column))
+			return
+		}
+	}
+	var columns []string
+	for _, column := range df.data.Fields {
+		if _, ok := aggs[column]; ok {
+			columns = append(columns, column)
+		}
+	}
+
+	// Order the rows by datetime, so that first and last follow time.
+	isNull := timeElements.IsNull()
+	nanos := timeElements.Values()
+	rows := make([]int, 0, len(nanos))
+	for i := range nanos {
+		if !isNull[i] {
+			rows = append(rows, i)
+		}
+	}
+	sort.SliceStable(rows, func(a, b int) bool {
+		return nanos[rows[a]] < nanos[rows[b]]
+	})
+
+	var windows [][]int
+	var starts []int64
+	if len(rows) > 0 {
+		origin := opts.Origin
+		if origin.IsZero() {
+			first := timeElements.Time(rows[0])
+			origin = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, first.Location())
+		}
+		originNano, step := origin.UnixNano(), int64(freq)
+		firstWindow := floorDiv(nanos[rows[0]]-originNano, step)
+		lastWindow := floorDiv(nanos[rows[len(rows)-1]]-originNano, step)
+		// The difference is negative when it overflows.
+		if span := lastWindow - firstWindow; span < 0 || span >= maxResampleWindows {
+			err = errors.New(fmt.Sprintf("frequency %q makes more than %d windows", frequency, maxResampleWindows))
+			return
+		}
+		windows = make([][]int, lastWindow-firstWindow+1)
+		starts = make([]int64, len(windows))
+		for i := range windows {
+			starts[i] = originNano + (firstWindow+int64(i))*step
+		}
+		for _, row := range rows {
+			window := floorDiv(nanos[row]-originNano, step) - firstWindow
+			windows[window] = append(windows[window], row)
+		}
+	}
+
+	arrays := make([]*ec.Array, len(columns)+1)
+	arrays[0] = &ec.Array{
+		FieldName: timeColumn,
+		Elements:  sdatetime.NewElementsDatetime(starts, nil, timeElements.TimeLocation()),
+	}
+	err = internal.ParallelEach(df.workers(), len(columns), func(i int) error {
+		se, _ := df.getOriginSeriesByColumn(columns[i])
+		newSe, e := se.aggregateWindows(aggs[columns[i]], windows)
+		if e == nil && aggs[columns[i]] != AggCount {
+			newSe, e = newSe.fillWindows(opts.Fill, windows)
+		}
+		if e != nil {
+			return fmt.Errorf("column %q error: %w", columns[i], e)
+		}
+		arrays[i+1] = newSe.array
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	newDataFrame, err = newFromArrays(arrays...)
+	return
+}
+
+// fillWindows fills the aggregates of the windows without rows, keeping
+// the null aggregates of the other windows. The windows without rows are
+// made null first, since sum gives them 0.
+func (se *Series) fillWindows(fill string, windows [][]int) (newSeries *Series, err error) {
+	newSeries = se
+	if fill == "" {
+		return
+	}
+	rows := make([]int, len(windows))
+	for i := range rows {
+		rows[i] = i
+		if len(windows[i]) == 0 {
+			rows[i] = -1
+		}
+	}
+	emptied, err := se.takeRows(rows)
+	if err != nil {
+		return
+	}
+	var filled *Series
+	switch fill {
+	case FillForward:
+		filled, err = emptied.FFill()
+	case InterpolateLinear, InterpolateNearest:
+		switch se.Type() {
+		case types.TypeInt, types.TypeFloat:
+			filled, err = emptied.Interpolate(fill)
+		case types.TypeDuration:
+			filled, err = emptied.interpolateDurations(fill)
+		}
+	}
+	if err != nil || filled == nil {
+		return
+	}
+	isNaN := se.IsNaN()
+	for i := range rows {
+		rows[i] = i
+		if len(windows[i]) > 0 && isNaN[i] {
+			rows[i] = -1
+		}
+	}
+	newSeries, err = filled.takeRows(rows)
+	return
+}
+
+// interpolateDurations interpolates a duration series through its
+// nanoseconds, rounded back to durations.
+func (se *Series) interpolateDurations(method string) (newSeries *Series, err error) {
+	nanos, _ := se.intValues()
+	isNull := se.IsNull()
+	values := make([]float64, len(nanos))
+	for i, value := range nanos {
+		values[i] = float64(value)
+	}
+	floats := se.newDerivedSeries(sfloat.NewNullableElementsFloat64(values, isNull))
+	interpolated, err := floats.Interpolate(method)
+	if err != nil {
+		return
+	}
+	values, isNaN, err := interpolated.floatValues()
+	if err != nil {
+		return
+	}
+	results := make([]int64, len(values))
+	for i, value := range values {
+		if !isNaN[i] {
+			results[i] = int64(math.Round(value))
+		}
+	}
+	newSeries = se.newDerivedSeries(sduration.NewElementsDuration(results, isNaN))
+	return
+}
+
+// aggregateWindows aggregates the rows of every window with agg. Count
+// returns an int series and sum returns 0 for an empty window, which
+// fillWindows fills, the other aggregations return null. First and last keep the type of the series,
+// the numeric aggregations return floats, or durations for a duration
+// series.
+func (se *Series) aggregateWindows(agg string, windows [][]int) (newSeries *Series, err error) {
+	isNaN := se.IsNaN()
+	switch agg {
+	case AggCount:
+		counts := make([]int64, len(windows))
+		for i, rows := range windows {
+			for _, row := range rows {
+				if !isNaN[row] {
+					counts[i]++
+				}
+			}
+		}
+		newSeries = se.newDerivedSeries(sint.NewElementsInt64(counts))
+		return
+	case AggFirst, AggLast:
+		// Empty windows take row -1, which is null.
+		picked := make([]int, len(windows))
+		for i, rows := range windows {
+			picked[i] = -1
+			for j := range rows {
+				row := rows[j]
+				if agg == AggLast {
+					row = rows[len(rows)-1-j]
+				}
+				if !isNaN[row] {
+					picked[i] = row
+					break
+				}
+			}
+		}
+		return se.takeRows(picked)
+	}
+
+	var f func(values []float64) float64
+	switch agg {
+	case AggSum:
+		f = sum
+	case AggMean:
+		f = mean
+	case AggMedian:
+		f = func(values []float64) float64 {
+			return quantile(values, 0.5)
+		}
+	case AggMin:
+		f = func(values []float64) float64 {
+			return quantile(values, 0)
+		}
+	case AggMax:
+		f = func(values []float64) float64 {
+			return quantile(values, 1)
+		}
+	default:
+		err = errors.New(fmt.Sprintf("unknown aggregation %q", agg))
+		return
+	}
+
+	values, _, err := se.floatValues()
+	if err != nil && se.Type() == types.TypeDuration {
+		ints, _ := se.intValues()
+		values, err = make([]float64, len(ints)), nil
+		for i, value := range ints {
+			values[i] = float64(value)
+		}
+	}
+	if err != nil {
+		return
+	}
+	results := make([]float64, len(windows))
+	for i, rows := range windows {
+		windowValues := make([]float64, 0, len(rows))
+		for _, row := range rows {
+			if !isNaN[row] {
+				windowValues = append(windowValues, values[row])
+			}
+		}
+		results[i] = math.NaN()
+		if len(windowValues) > 0 || agg == AggSum {
+			results[i] = f(windowValues)
+		}
+	}
+
+	resultNull := make([]bool, len(results))
+	for i, result := range results {
+		resultNull[i] = math.IsNaN(result)
+	}
+	if se.Type() == types.TypeDuration {
+		nanos := make([]int64, len(results))
+		for i, result := range results {
+			if !resultNull[i] {
+				nanos[i] = int64(math.Round(result))
+			}
+		}
+		newSeries = se.newDerivedSeries(sduration.NewElementsDuration(nanos, resultNull))
+		return
+	}
+	newSeries = se.newDerivedSeries(sfloat.NewNullableElementsFloat64(results, resultNull))
+	return
+}
@@ -0,0 +1,161 @@
+package godas
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateRange(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	se, err := DateRange(start, start.Add(time.Hour), 20*time.Minute, "At")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := se.array.Elements.String(); got != "[2020-01-01T00:00:00Z 2020-01-01T00:20:00Z 2020-01-01T00:40:00Z]" {
+		t.Errorf("date range: got %s", got)
+	}
+	se, _ = DateRange(start, start.Add(-time.Hour), -time.Hour, "At")
+	if se.Len() != 1 {
+		t.Errorf("negative step: got %d datetimes, want 1", se.Len())
+	}
+	if _, err = DateRange(start, start, 0, "At"); err == nil {
+		t.Error("expected a zero step error")
+	}
+}
+
+func TestDataFrameResample(t *testing.T) {
+	type sample struct {
+		At      time.Time
+		Value   *float64
+		Host    string
+		Latency time.Duration
+	}
+	base := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	one, two, six := 1.0, 2.0, 6.0
+	df, err := NewFromStructs([]sample{
+		{base.Add(7 * time.Minute), &two, "b", 3 * time.Second},
+		{base.Add(time.Minute), &one, "a", time.Second},
+		{base.Add(21 * time.Minute), &six, "c", time.Second},
+		{base.Add(8 * time.Minute), nil, "d", time.Second},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aggs := map[string]string{"Value": AggMean, "Host": AggLast, "Latency": AggMax}
+	resampled, err := df.Resample("At", "5m", aggs, ResampleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ := resampled.GetSeriesByColumn("At")
+	if got := se.array.Elements.String(); got != "[2020-01-01T10:00:00Z 2020-01-01T10:05:00Z 2020-01-01T10:10:00Z 2020-01-01T10:15:00Z 2020-01-01T10:20:00Z]" {
+		t.Errorf("windows: got %s", got)
+	}
+	se, _ = resampled.GetSeriesByColumn("Value")
+	if got := se.array.Elements.String(); got != "[1 2 null null 6]" {
+		t.Errorf("mean: got %s", got)
+	}
+	se, _ = resampled.GetSeriesByColumn("Host")
+	if got := se.array.Elements.String(); got != "[a d null null c]" {
+		t.Errorf("last: got %s", got)
+	}
+	se, _ = resampled.GetSeriesByColumn("Latency")
+	if got := se.array.Elements.String(); got != "[1s 3s null null 1s]" {
+		t.Errorf("max: got %s", got)
+	}
+
+	filled, _ := df.Resample("At", "5m", map[string]string{"Value": AggMean}, ResampleOptions{Fill: InterpolateLinear})
+	se, _ = filled.GetSeriesByColumn("Value")
+	if got := se.array.Elements.String(); got != "[1 2 3.333333333333333 4.666666666666666 6]" {
+		t.Errorf("interpolate: got %s", got)
+	}
+	counted, _ := df.Resample("At", "5m", map[string]string{"Value": AggCount}, ResampleOptions{Fill: FillForward})
+	se, _ = counted.GetSeriesByColumn("Value")
+	if got := se.array.Elements.String(); got != "[1 1 0 0 1]" {
+		t.Errorf("count: got %s", got)
+	}
+
+	sums := map[string]string{"": "[1 2 0 0 6]", FillForward: "[1 2 2 2 6]", InterpolateNearest: "[1 2 2 6 6]"}
+	for fill, want := range sums {
+		summed, err := df.Resample("At", "5m", map[string]string{"Value": AggSum}, ResampleOptions{Fill: fill})
+		if err != nil {
+			t.Fatal(err)
+		}
+		se, _ = summed.GetSeriesByColumn("Value")
+		if got := se.array.Elements.String(); got != want {
+			t.Errorf("sum with fill %q: got %s, want %s", fill, got, want)
+		}
+	}
+
+	origin := base.Add(2 * time.Minute)
+	shifted, _ := df.Resample("At", "10m", map[string]string{"Host": AggFirst}, ResampleOptions{Origin: origin, Fill: FillForward})
+	se, _ = shifted.GetSeriesByColumn("At")
+	if got := se.array.Elements.String(); got != "[2020-01-01T09:52:00Z 2020-01-01T10:02:00Z 2020-01-01T10:12:00Z]" {
+		t.Errorf("origin: got %s", got)
+	}
+	se, _ = shifted.GetSeriesByColumn("Host")
+	if got := se.array.Elements.String(); got != "[a b c]" {
+		t.Errorf("first: got %s", got)
+	}
+
+	daily, _ := df.Resample("At", "1d", map[string]string{"Latency": AggSum}, ResampleOptions{})
+	se, _ = daily.GetSeriesByColumn("Latency")
+	if got := se.array.Elements.String(); got != "[6s]" {
+		t.Errorf("daily sum: got %s", got)
+	}
+
+	if _, err = df.Resample("Host", "5m", aggs, ResampleOptions{}); err == nil {
+		t.Error("expected a datetime column error")
+	}
+	if _, err = df.Resample("At", "5x", aggs, ResampleOptions{}); err == nil {
+		t.Error("expected a frequency error")
+	}
+	if _, err = df.Resample("At", "1ns", aggs, ResampleOptions{}); err == nil {
+		t.Error("expected a window count error")
+	}
+	if _, err = df.Resample("At", "5m", map[string]string{"At": AggFirst}, ResampleOptions{}); err == nil {
+		t.Error("expected a time column aggregation error")
+	}
+	if _, err = df.Resample("At", "5m", map[string]string{"Host": AggMean}, ResampleOptions{}); err == nil {
+		t.Error("expected a numeric aggregation error")
+	}
+}
+
+func TestDataFrameResampleFill(t *testing.T) {
+	type sample struct {
+		At      time.Time
+		Value   *float64
+		Latency *time.Duration
+	}
+	base := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	one, four := 1.0, 4.0
+	second, seconds := time.Second, 4*time.Second
+	df, err := NewFromStructs([]sample{
+		{base, &one, &second},
+		{base.Add(5 * time.Minute), nil, nil},
+		{base.Add(15 * time.Minute), &four, &seconds},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second window has a row but no value, only the third is empty.
+	aggs := map[string]string{"Value": AggMean, "Latency": AggMax}
+	cases := map[string][2]string{
+		FillForward:        {"[1 null 1 4]", "[1s null 1s 4s]"},
+		InterpolateLinear:  {"[1 null 3 4]", "[1s null 3s 4s]"},
+		InterpolateNearest: {"[1 null 4 4]", "[1s null 4s 4s]"},
+	}
+	for fill, want := range cases {
+		resampled, err := df.Resample("At", "5m", aggs, ResampleOptions{Fill: fill})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, column := range []string{"Value", "Latency"} {
+			se, _ := resampled.GetSeriesByColumn(column)
+			if got := se.array.Elements.String(); got != want[i] {
+				t.Errorf("%s %s: got %s, want %s", fill, column, got, want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/internal"
+	ec "github.com/hunknownz/godas/internal/elements_composite"
+	sstring "github.com/hunknownz/godas/internal/elements_string"
+	"github.com/hunknownz/godas/types"
+	"math"
+)
+
+const describeStatColumn = "Stat"
+
+var (
+	describeNumericStats     = []string{"count", "mean", "std", "min", "25%", "50%", "75%", "max"}
+	describeCategoricalStats = []string{"count", "unique", "top", "freq"}
+	describeMixedStats       = []string{"count", "unique", "top", "freq", "mean", "std", "min", "25%", "50%", "75%", "max"}
+)
+
+func (se *Series) describeNumeric() (stats map[string]float64) {
+	values, _, _ := se.numericValues(true)
+	stats = map[string]float64{
+		"count": float64(len(values)),
+		"mean":  mean(values),
+		"std":   math.Sqrt(variance(values)),
+		"min":   quantile(values, 0),
+		"25%":   quantile(values, 0.25),
+		"50%":   quantile(values, 0.5),
+		"75%":   quantile(values, 0.75),
+		"max":   quantile(values, 1),
+	}
+	return
+}
+
+func (se *Series) describeCategorical() (stats map[string]interface{}, err error) {
+	isNaN := se.IsNaN()
+	seLen := se.Len()
+	counts := make(map[interface{}]int64)
+	var top interface{}
+	var freq, count int64
+	for i := 0; i < seLen; i++ {
+		if isNaN[i] {
+			continue
+		}
+		var value interface{}
+		switch se.array.Elements.(type) {
+		case sstring.ElementsString:
+			value = se.array.Elements.(sstring.ElementsString).Values()[i]
+		default:
+			element, e := se.array.At(i)
+			if e != nil {
+				err = e
+				return
+			}
+			value = element.Value
+		}
+		count++
+		counts[value]++
+		if counts[value] > freq {
+			top, freq = value, counts[value]
+		}
+	}
+
+	stats = map[string]interface{}{
+		"count":  count,
+		"unique": int64(len(counts)),
+		"top":    top,
+		"freq":   freq,
+	}
+	return
+}
+
+// Describe returns a summary frame with one column per described column and
+// the statistic names in the Stat column. Int and float columns get count,
+// mean, std, min, quartiles and max, string and bool columns get count,
+// unique, top and freq.
+func (df *DataFrame) Describe() (newDataFrame *DataFrame, err error) {
+	data := df.data
+	numeric, categorical := 0, 0
+	for _, array := range data.NArray {
+		switch array.Type() {
+		case types.TypeInt, types.TypeFloat:
+			numeric++
+		case types.TypeString, types.TypeBool:
+			categorical++
+		}
+	}
+	if numeric+categorical == 0 {
+		err = errors.New("describe error: no columns to describe")
+		return
+	}
+
+	var labels []string
+	switch {
+	case categorical == 0:
+		labels = describeNumericStats
+	case numeric == 0:
+		labels = describeCategoricalStats
+	default:
+		labels = describeMixedStats
+	}
+
+	statArray, _ := ec.NewArray(labels, describeStatColumn)
+	described := make([]*ec.Array, len(data.NArray))
+	err = internal.ParallelEach(df.workers(), len(data.NArray), func(i int) (e error) {
+		described[i], e = describeArray(data.NArray[i], labels)
+		return
+	})
+	if err != nil {
+		err = fmt.Errorf("describe error: %w", err)
+		return
+	}
+	arrays := []*ec.Array{statArray}
+	for _, array := range described {
+		if array != nil {
+			arrays = append(arrays, array)
+		}
+	}
+
+	newDataFrame, err = newFromArrays(arrays...)
+	if err != nil {
+		err = fmt.Errorf("describe error: %w", err)
+	}
+	return
+}
+
+// describeArray returns the described column of array with the statistics
+// in labels, or nil if array isn't described.
+func describeArray(array *ec.Array, labels []string) (newArray *ec.Array, err error) {
+	se := &Series{
+		array: array,
+	}
+	var values interface{}
+	switch array.Type() {
+	case types.TypeInt, types.TypeFloat:
+		stats := se.describeNumeric()
+		floatValues := make([]float64, len(labels))
+		for i, label := range labels {
+			value, ok := stats[label]
+			if !ok {
+				value = math.NaN()
+			}
+			floatValues[i] = value
+		}
+		values = floatValues
+	case types.TypeString, types.TypeBool:
+		stats, e := se.describeCategorical()
+		if e != nil {
+			err = fmt.Errorf("describe column %q error: %w", array.FieldName, e)
+			return
+		}
+		objectValues := make([]interface{}, len(labels))
+		for i, label := range labels {
+			objectValues[i] = stats[label]
+		}
+		values = objectValues
+	default:
+		return
+	}
+
+	newArray, err = ec.NewArray(values, array.FieldName)
+	return
+}
+
+const pairwiseLabelColumn = "Column"
+
+// pairwiseMatrix evaluates f on every pair of int and float columns and
+// returns the square frame, labeled by the numeric columns in the Column
+// column.
+func (df *DataFrame) pairwiseMatrix(f func(xs, ys []float64) (float64, error)) (newDataFrame *DataFrame, err error) {
+	var names []string
+	var values [][]float64
+	var masks [][]bool
+	for _, array := range df.data.NArray {
+		typ := array.Type()
+		if typ != types.TypeInt && typ != types.TypeFloat {
+			continue
+		}
+		se := &Series{
+			array: array,
+		}
+		x, isNaN, _ := se.floatValues()
+		names = append(names, array.FieldName)
+		values = append(values, x)
+		masks = append(masks, isNaN)
+	}
+	if len(names) == 0 {
+		err = errors.New("no numeric columns")
+		return
+	}
+
+	columnNum := len(names)
+	matrix := make([][]float64, columnNum)
+	for i := range matrix {
+		matrix[i] = make([]float64, columnNum)
+	}
+	// Row i computes the pairs (i, j) with j >= i and writes matrix[i][j]
+	// and matrix[j][i], which no other row writes.
+	err = internal.ParallelEach(df.workers(), columnNum, func(i int) error {
+		for j := i; j < columnNum; j++ {
+			xs, ys := pairwiseComplete(values[i], values[j], masks[i], masks[j])
+			value, e := f(xs, ys)
+			if e != nil {
+				return e
+			}
+			matrix[i][j], matrix[j][i] = value, value
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	labelArray, _ := ec.NewArray(names, pairwiseLabelColumn)
+	arrays := []*ec.Array{labelArray}
+	for j, name := range names {
+		column := make([]float64, columnNum)
+		for i := 0; i < columnNum; i++ {
+			column[i] = matrix[i][j]
+		}
+		newArray, _ := ec.NewArray(column, name)
+		arrays = append(arrays, newArray)
+	}
+	newDataFrame, err = newFromArrays(arrays...)
+	return
+}
+
+// Corr returns the correlation matrix of the int and float columns using
+// CorrPearson, CorrSpearman or CorrKendall. NaN elements are handled with
+// pairwise complete observations.
+func (df *DataFrame) Corr(method string) (newDataFrame *DataFrame, err error) {
+	newDataFrame, err = df.pairwiseMatrix(func(xs, ys []float64) (float64, error) {
+		return correlation(method, xs, ys)
+	})
+	if err != nil {
+		err = fmt.Errorf("corr error: %w", err)
+	}
+	return
+}
+
+// Cov returns the sample covariance matrix of the int and float columns
+// using pairwise complete observations.
+func (df *DataFrame) Cov() (newDataFrame *DataFrame, err error) {
+	newDataFrame, err = df.pairwiseMatrix(func(xs, ys []float64) (float64, error) {
+		return covariance(xs, ys), nil
+	})
+	if err != nil {
+		err = fmt.Errorf("cov error: %w", err)
+	}
+	return
+}
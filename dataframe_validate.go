@@ -0,0 +1,291 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/condition"
+	"github.com/hunknownz/godas/expression"
+	"github.com/hunknownz/godas/internal"
+	"github.com/hunknownz/godas/types"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError aggregates every problem found by ValidateCondition.
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid condition: %s", strings.Join(msgs, "; "))
+}
+
+// ValidateCondition checks cond against the columns of df before it's
+// evaluated: every referenced column must exist, every comparator must be
+// legal for the column type and every literal must be comparable with it.
+// Compatible literals are coerced in place, such as "18" or 18.0 for an int
+// column. All problems are returned in one *ValidationError.
+func (df *DataFrame) ValidateCondition(cond *condition.Condition) (err error) {
+	validationErr := new(ValidationError)
+	df.validateExpr(cond.Prepare(), validationErr)
+	if len(validationErr.Errors) > 0 {
+		err = validationErr
+	}
+	return
+}
+
+func (df *DataFrame) validateExpr(expr condition.ExprAST, validationErr *ValidationError) {
+	switch expr.(type) {
+	case condition.BinaryExprAST:
+		ast := expr.(condition.BinaryExprAST)
+		df.validateExpr(ast.Lhs, validationErr)
+		df.validateExpr(ast.Rhs, validationErr)
+	case condition.ValueExprAST:
+		condVal := expr.(condition.ValueExprAST).Value
+		if condVal.Cond != nil {
+			df.validateExpr(condVal.Cond.Prepare(), validationErr)
+			return
+		}
+		err := df.validateCompItem(condVal.CompItem)
+		if err != nil {
+			validationErr.Errors = append(validationErr.Errors, err)
+		}
+	}
+}
+
+func (df *DataFrame) columnType(column string) (typ types.Type, err error) {
+	arrayI, ok := df.data.FieldArraysMap[column]
+	if !ok {
+		err = errors.New(fmt.Sprintf("column name %q not found", column))
+		return
+	}
+	typ = df.data.NArray[arrayI].Type()
+	return
+}
+
+func isOrderedComparator(comparator string) bool {
+	switch comparator {
+	case condition.ComparatorEq, condition.ComparatorNotEq,
+		condition.ComparatorLT, condition.ComparatorLTE,
+		condition.ComparatorGT, condition.ComparatorGTE:
+		return true
+	}
+	return false
+}
+
+func isStringComparator(comparator string) bool {
+	switch comparator {
+	case condition.ComparatorContains, condition.ComparatorStartsWith,
+		condition.ComparatorEndsWith, condition.ComparatorRegex,
+		condition.ComparatorEqFold, condition.ComparatorContainsFold,
+		condition.ComparatorStartsWithFold, condition.ComparatorEndsWithFold,
+		condition.ComparatorRegexFold:
+		return true
+	}
+	return false
+}
+
+// isComparatorSupported reports whether comparator is legal for a column
+// of type typ.
+func isComparatorSupported(typ types.Type, comparator string) bool {
+	switch {
+	case condition.IsNaNComparator(comparator), comparator == condition.ComparatorFunc,
+		comparator == condition.ComparatorIn, comparator == condition.ComparatorNotIn,
+		comparator == condition.ComparatorEq, comparator == condition.ComparatorNotEq:
+		return true
+	case isOrderedComparator(comparator), comparator == condition.ComparatorBetween:
+		return typ != types.TypeObject
+	case isStringComparator(comparator):
+		return typ == types.TypeString
+	}
+	return false
+}
+
+func (df *DataFrame) validateCompItem(item *condition.CompItem) (err error) {
+	comparator := item.Comparator
+	if comparator == condition.ComparatorRowFunc {
+		if f, ok := item.Value.(condition.RowFunc); !ok || f == nil {
+			err = errors.New("row func value must be a non-nil condition.RowFunc")
+		}
+		return
+	}
+
+	typ, err := df.columnType(item.Column)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("column %q of type %s: %w", item.Column, typ, err)
+		}
+	}()
+	if !isComparatorSupported(typ, comparator) {
+		err = errors.New(fmt.Sprintf("comparator %q is not supported", comparator))
+		return
+	}
+
+	switch {
+	case condition.IsNaNComparator(comparator):
+	case comparator == condition.ComparatorFunc:
+		if f, ok := item.Value.(condition.ElementFunc); !ok || f == nil {
+			err = errors.New("func value must be a non-nil condition.ElementFunc")
+		}
+	case isOrderedComparator(comparator):
+		if ref, ok := item.Value.(condition.ColumnRef); ok {
+			err = df.validateColumnRef(typ, ref)
+			return
+		}
+		var value interface{}
+		value, err = coerceLiteral(typ, item.Value)
+		if err == nil {
+			item.SetValue(value)
+		}
+	case comparator == condition.ComparatorBetween:
+		r, ok := item.Value.(condition.Range)
+		if !ok {
+			err = errors.New(fmt.Sprintf("between value %v must be a condition.Range", item.Value))
+			return
+		}
+		r.Low, err = coerceLiteral(typ, r.Low)
+		if err != nil {
+			return
+		}
+		r.High, err = coerceLiteral(typ, r.High)
+		if err == nil {
+			item.SetValue(r)
+		}
+	case comparator == condition.ComparatorIn, comparator == condition.ComparatorNotIn:
+		values, ok := item.Value.([]interface{})
+		if !ok {
+			err = errors.New(fmt.Sprintf("%s value %v must be a slice", comparator, item.Value))
+			return
+		}
+		coerced := make([]interface{}, len(values))
+		for i, value := range values {
+			coerced[i], err = coerceLiteral(typ, value)
+			if err != nil {
+				return
+			}
+		}
+		item.SetValue(coerced)
+	case isStringComparator(comparator):
+		pattern, ok := item.Value.(string)
+		if !ok {
+			err = errors.New(fmt.Sprintf("%s value %v must be a string", comparator, item.Value))
+			return
+		}
+		if comparator == condition.ComparatorRegex || comparator == condition.ComparatorRegexFold {
+			_, err = regexp.Compile(pattern)
+		}
+	}
+	return
+}
+
+// validateColumnRef checks that the columns of ref exist and can be compared
+// with a column of type typ.
+func (df *DataFrame) validateColumnRef(typ types.Type, ref condition.ColumnRef) (err error) {
+	if typ != types.TypeInt && typ != types.TypeFloat && typ != types.TypeString {
+		err = errors.New(fmt.Sprintf("can't compare with %s", ref))
+		return
+	}
+	for _, column := range expression.Columns(ref.Expr) {
+		refTyp, e := df.columnType(column)
+		if e != nil {
+			err = e
+			return
+		}
+		if (refTyp == types.TypeString) != (typ == types.TypeString) ||
+			(refTyp != types.TypeInt && refTyp != types.TypeFloat && refTyp != types.TypeString) {
+			err = errors.New(fmt.Sprintf("can't compare with column %q of type %s", column, refTyp))
+			return
+		}
+	}
+	return
+}
+
+// coerceLiteral converts value to the literal type of a column of type typ
+// when they're compatible. Datetime columns accept RFC 3339 strings and
+// duration columns Go or ISO 8601 duration strings.
+func coerceLiteral(typ types.Type, value interface{}) (coerced interface{}, err error) {
+	coerced = value
+	switch typ {
+	case types.TypeInt:
+		switch value.(type) {
+		case int64:
+			return
+		case float64:
+			floatValue := value.(float64)
+			if floatValue == math.Trunc(floatValue) && math.Abs(floatValue) < 1<<63 {
+				coerced = int64(floatValue)
+			}
+			return
+		case string:
+			if intValue, e := strconv.ParseInt(value.(string), 10, 64); e == nil {
+				coerced = intValue
+				return
+			}
+			if floatValue, e := strconv.ParseFloat(value.(string), 64); e == nil {
+				coerced, err = coerceLiteral(typ, floatValue)
+				return
+			}
+		}
+	case types.TypeFloat:
+		switch value.(type) {
+		case float64:
+			return
+		case int64:
+			coerced = float64(value.(int64))
+			return
+		case string:
+			if floatValue, e := strconv.ParseFloat(value.(string), 64); e == nil {
+				coerced = floatValue
+				return
+			}
+		}
+	case types.TypeString:
+		if _, ok := value.(string); ok {
+			return
+		}
+	case types.TypeBool:
+		switch value.(type) {
+		case bool:
+			return
+		case string:
+			if boolValue, e := strconv.ParseBool(value.(string)); e == nil {
+				coerced = boolValue
+				return
+			}
+		}
+	case types.TypeDatetime:
+		switch value.(type) {
+		case time.Time:
+			return
+		case string:
+			if timeValue, e := time.Parse(time.RFC3339Nano, value.(string)); e == nil {
+				coerced = timeValue
+				return
+			}
+		}
+	case types.TypeDuration:
+		switch value.(type) {
+		case time.Duration:
+			return
+		case string:
+			if durationValue, e := internal.ParseDuration(value.(string)); e == nil {
+				coerced = durationValue
+				return
+			}
+		}
+	case types.TypeObject:
+		return
+	}
+	err = errors.New(fmt.Sprintf("can't compare with %T value %v", value, value))
+	return
+}
@@ -0,0 +1,57 @@
+package godas
+
+import (
+	"errors"
+	"github.com/hunknownz/godas/condition"
+	"testing"
+)
+
+func TestDataFrameValidateCondition(t *testing.T) {
+	type person struct {
+		Name   string
+		Age    int
+		Score  float64
+		Active bool
+	}
+	df, _ := NewFromStructs([]person{
+		{"Anna", 17, 1.5, true},
+		{"Ben", 34, 2.5, false},
+	})
+
+	cond := NewDataFrameCondition()
+	cond.And(">=", "18", "Age").
+		And("in", []interface{}{1, "2.5"}, "Score").
+		And("=", "true", "Active").
+		And("between", condition.Between(17.0, 40.0), "Age").
+		And("<", condition.Col("Age"), "Score")
+	if err := df.ValidateCondition(cond); err != nil {
+		t.Fatal(err)
+	}
+	newDataFrame, err := df.Filter(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newDataFrame.NumRow() != 0 {
+		t.Errorf("got %d rows, want 0", newDataFrame.NumRow())
+	}
+	item := cond.Prepare().(condition.BinaryExprAST).Lhs.(condition.ValueExprAST).Value.CompItem
+	if item.Value != int64(18) {
+		t.Errorf("age literal coerced to %T %v", item.Value, item.Value)
+	}
+
+	cond = NewDataFrameCondition()
+	cond.And(">", 1, "Agee").
+		Or("contains", "a", "Age").
+		Or("=", "x", "Score").
+		Or("regex", "(", "Name").
+		Or("<", condition.Col("Name"), "Age").
+		Or("is_nan", nil, "Name")
+	err = df.ValidateCondition(cond)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if len(validationErr.Errors) != 5 {
+		t.Errorf("got %d errors, want 5: %v", len(validationErr.Errors), err)
+	}
+}
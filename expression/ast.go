@@ -0,0 +1,140 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Node interface {
+	String() string
+	Position() int
+}
+
+// NumberNode holds an int64 or a float64 literal.
+type NumberNode struct {
+	Value interface{}
+	Pos   int
+}
+
+func (node NumberNode) String() string {
+	return fmt.Sprintf("%v", node.Value)
+}
+
+func (node NumberNode) Position() int {
+	return node.Pos
+}
+
+type StringNode struct {
+	Value string
+	Pos   int
+}
+
+func (node StringNode) String() string {
+	return fmt.Sprintf("%q", node.Value)
+}
+
+func (node StringNode) Position() int {
+	return node.Pos
+}
+
+type BoolNode struct {
+	Value bool
+	Pos   int
+}
+
+func (node BoolNode) String() string {
+	return fmt.Sprintf("%t", node.Value)
+}
+
+func (node BoolNode) Position() int {
+	return node.Pos
+}
+
+type ColumnNode struct {
+	Name string
+	Pos  int
+}
+
+func (node ColumnNode) String() string {
+	return fmt.Sprintf("`%s`", node.Name)
+}
+
+func (node ColumnNode) Position() int {
+	return node.Pos
+}
+
+type UnaryNode struct {
+	Op      string
+	Operand Node
+	Pos     int
+}
+
+func (node UnaryNode) String() string {
+	return fmt.Sprintf("%s%s", node.Op, node.Operand)
+}
+
+func (node UnaryNode) Position() int {
+	return node.Pos
+}
+
+type BinaryNode struct {
+	Op string
+	Lhs,
+	Rhs Node
+	Pos int
+}
+
+func (node BinaryNode) String() string {
+	return fmt.Sprintf("(%s %s %s)", node.Lhs, node.Op, node.Rhs)
+}
+
+func (node BinaryNode) Position() int {
+	return node.Pos
+}
+
+type CallNode struct {
+	Func string
+	Args []Node
+	Pos  int
+}
+
+func (node CallNode) String() string {
+	args := make([]string, len(node.Args))
+	for i, arg := range node.Args {
+		args[i] = arg.String()
+	}
+	return fmt.Sprintf("%s(%s)", node.Func, strings.Join(args, ", "))
+}
+
+func (node CallNode) Position() int {
+	return node.Pos
+}
+
+// Columns returns the names of the columns referenced by node, in order of
+// first reference.
+func Columns(node Node) (columns []string) {
+	seen := make(map[string]bool)
+	var walk func(node Node)
+	walk = func(node Node) {
+		switch node.(type) {
+		case ColumnNode:
+			name := node.(ColumnNode).Name
+			if !seen[name] {
+				seen[name] = true
+				columns = append(columns, name)
+			}
+		case UnaryNode:
+			walk(node.(UnaryNode).Operand)
+		case BinaryNode:
+			binNode := node.(BinaryNode)
+			walk(binNode.Lhs)
+			walk(binNode.Rhs)
+		case CallNode:
+			for _, arg := range node.(CallNode).Args {
+				walk(arg)
+			}
+		}
+	}
+	walk(node)
+	return
+}
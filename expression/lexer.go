@@ -0,0 +1,133 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	TokenEOF = iota
+	TokenNumber
+	TokenString
+	TokenIdent
+	TokenOperator
+	TokenLeftParen
+	TokenRightParen
+	TokenComma
+)
+
+type Token struct {
+	Type   int
+	Text   string
+	Pos    int
+	Quoted bool
+}
+
+func (token Token) String() string {
+	if token.Type == TokenEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q", token.Text)
+}
+
+// ParseError is returned for a malformed expression. Pos is the byte offset
+// of the offending token in the source.
+type ParseError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse error at position %d: %s", e.Pos, e.Msg)
+}
+
+func newParseError(pos int, format string, args ...interface{}) *ParseError {
+	return &ParseError{
+		Pos: pos,
+		Msg: fmt.Sprintf(format, args...),
+	}
+}
+
+var operators = []string{
+	"&&", "||", "==", "!=", "<=", ">=",
+	"+", "-", "*", "/", "%", "<", ">", "=", "!",
+}
+
+// Tokenize splits source into tokens. Identifiers may be quoted with
+// backticks when they contain spaces, string literals use single or double
+// quotes.
+func Tokenize(source string) (tokens []Token, err error) {
+	pos := 0
+	for pos < len(source) {
+		c, size := utf8.DecodeRuneInString(source[pos:])
+		switch {
+		case unicode.IsSpace(c):
+			pos += size
+		case c == '(':
+			tokens = append(tokens, Token{Type: TokenLeftParen, Text: "(", Pos: pos})
+			pos++
+		case c == ')':
+			tokens = append(tokens, Token{Type: TokenRightParen, Text: ")", Pos: pos})
+			pos++
+		case c == ',':
+			tokens = append(tokens, Token{Type: TokenComma, Text: ",", Pos: pos})
+			pos++
+		case c == '`' || c == '\'' || c == '"':
+			end := strings.IndexRune(source[pos+1:], c)
+			if end < 0 {
+				err = newParseError(pos, "unterminated quote %q", string(c))
+				return
+			}
+			text := source[pos+1 : pos+1+end]
+			if c == '`' {
+				tokens = append(tokens, Token{Type: TokenIdent, Text: text, Pos: pos, Quoted: true})
+			} else {
+				tokens = append(tokens, Token{Type: TokenString, Text: text, Pos: pos})
+			}
+			pos += end + 2
+		case unicode.IsDigit(c) || (c == '.' && pos+1 < len(source) && unicode.IsDigit(rune(source[pos+1]))):
+			start := pos
+			for pos < len(source) && (unicode.IsDigit(rune(source[pos])) || source[pos] == '.') {
+				pos++
+			}
+			if pos < len(source) && (source[pos] == 'e' || source[pos] == 'E') {
+				pos++
+				if pos < len(source) && (source[pos] == '+' || source[pos] == '-') {
+					pos++
+				}
+				for pos < len(source) && unicode.IsDigit(rune(source[pos])) {
+					pos++
+				}
+			}
+			tokens = append(tokens, Token{Type: TokenNumber, Text: source[start:pos], Pos: start})
+		case unicode.IsLetter(c) || c == '_':
+			start := pos
+			for pos < len(source) {
+				r, rSize := utf8.DecodeRuneInString(source[pos:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+					break
+				}
+				pos += rSize
+			}
+			tokens = append(tokens, Token{Type: TokenIdent, Text: source[start:pos], Pos: start})
+		default:
+			matched := false
+			for _, operator := range operators {
+				if strings.HasPrefix(source[pos:], operator) {
+					tokens = append(tokens, Token{Type: TokenOperator, Text: operator, Pos: pos})
+					pos += len(operator)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				err = newParseError(pos, "unexpected character %q", string(c))
+				return
+			}
+		}
+	}
+	tokens = append(tokens, Token{Type: TokenEOF, Pos: len(source)})
+	return
+}
@@ -0,0 +1,249 @@
+package expression
+
+import (
+	"strconv"
+)
+
+var precedence = map[string]int{
+	"||": 20,
+	"&&": 40,
+	"=":  60,
+	"==": 60,
+	"!=": 60,
+	"<":  60,
+	"<=": 60,
+	">":  60,
+	">=": 60,
+	"+":  80,
+	"-":  80,
+	"*":  100,
+	"/":  100,
+	"%":  100,
+}
+
+type parser struct {
+	tokens []Token
+	pos    int
+}
+
+func newParser(source string) (p *parser, err error) {
+	tokens, err := Tokenize(source)
+	if err != nil {
+		return
+	}
+	p = &parser{
+		tokens: tokens,
+	}
+	return
+}
+
+func (p *parser) peek() Token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() Token {
+	token := p.tokens[p.pos]
+	if token.Type != TokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+func (p *parser) expect(tokenType int, text string) (token Token, err error) {
+	token = p.next()
+	if token.Type != tokenType || (text != "" && token.Text != text) {
+		err = newParseError(token.Pos, "expected %q, found %s", text, token)
+	}
+	return
+}
+
+func (p *parser) parseExpression(minPrec int) (node Node, err error) {
+	node, err = p.parseUnary()
+	if err != nil {
+		return
+	}
+	for {
+		token := p.peek()
+		prec, ok := precedence[token.Text]
+		if token.Type != TokenOperator || !ok || prec < minPrec {
+			return
+		}
+		p.next()
+		rhs, e := p.parseExpression(prec + 1)
+		if e != nil {
+			err = e
+			return
+		}
+		node = BinaryNode{
+			Op:  token.Text,
+			Lhs: node,
+			Rhs: rhs,
+			Pos: token.Pos,
+		}
+	}
+}
+
+func (p *parser) parseUnary() (node Node, err error) {
+	token := p.peek()
+	if token.Type == TokenOperator && (token.Text == "-" || token.Text == "!") {
+		p.next()
+		operand, e := p.parseUnary()
+		if e != nil {
+			err = e
+			return
+		}
+		if number, ok := operand.(NumberNode); ok && token.Text == "-" {
+			switch number.Value.(type) {
+			case int64:
+				number.Value = -number.Value.(int64)
+			case float64:
+				number.Value = -number.Value.(float64)
+			}
+			number.Pos = token.Pos
+			node = number
+			return
+		}
+		node = UnaryNode{
+			Op:      token.Text,
+			Operand: operand,
+			Pos:     token.Pos,
+		}
+		return
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node Node, err error) {
+	token := p.next()
+	switch token.Type {
+	case TokenNumber:
+		if intValue, e := strconv.ParseInt(token.Text, 10, 64); e == nil {
+			node = NumberNode{Value: intValue, Pos: token.Pos}
+			return
+		}
+		floatValue, e := strconv.ParseFloat(token.Text, 64)
+		if e != nil {
+			err = newParseError(token.Pos, "invalid number %s", token)
+			return
+		}
+		node = NumberNode{Value: floatValue, Pos: token.Pos}
+	case TokenString:
+		node = StringNode{Value: token.Text, Pos: token.Pos}
+	case TokenIdent:
+		if token.Quoted {
+			node = ColumnNode{Name: token.Text, Pos: token.Pos}
+			return
+		}
+		switch token.Text {
+		case "true", "false":
+			node = BoolNode{Value: token.Text == "true", Pos: token.Pos}
+			return
+		}
+		if p.peek().Type == TokenLeftParen {
+			node, err = p.parseCall(token)
+			return
+		}
+		node = ColumnNode{Name: token.Text, Pos: token.Pos}
+	case TokenLeftParen:
+		node, err = p.parseExpression(0)
+		if err != nil {
+			return
+		}
+		_, err = p.expect(TokenRightParen, ")")
+	default:
+		err = newParseError(token.Pos, "unexpected %s", token)
+	}
+	return
+}
+
+func (p *parser) parseCall(name Token) (node Node, err error) {
+	p.next()
+	call := CallNode{
+		Func: name.Text,
+		Pos:  name.Pos,
+	}
+	if p.peek().Type == TokenRightParen {
+		p.next()
+		node = call
+		return
+	}
+	for {
+		arg, e := p.parseExpression(0)
+		if e != nil {
+			err = e
+			return
+		}
+		call.Args = append(call.Args, arg)
+		token := p.next()
+		if token.Type == TokenRightParen {
+			break
+		}
+		if token.Type != TokenComma {
+			err = newParseError(token.Pos, "expected \",\" or \")\", found %s", token)
+			return
+		}
+	}
+	node = call
+	return
+}
+
+func (p *parser) expectEOF() (err error) {
+	token := p.peek()
+	if token.Type != TokenEOF {
+		err = newParseError(token.Pos, "unexpected %s", token)
+	}
+	return
+}
+
+// Parse parses an arithmetic expression over columns, numeric, string and
+// bool literals with + - * / %, comparisons, && || !, parentheses and
+// function calls.
+func Parse(source string) (node Node, err error) {
+	p, err := newParser(source)
+	if err != nil {
+		return
+	}
+	node, err = p.parseExpression(0)
+	if err != nil {
+		return
+	}
+	err = p.expectEOF()
+	return
+}
+
+// ParseOperand parses an arithmetic operand of a comparison from
+// tokens[start:], stopping before a comparison, && or ||. It returns the
+// index of the first token after the operand.
+func ParseOperand(tokens []Token, start int) (node Node, end int, err error) {
+	p := &parser{
+		tokens: tokens,
+		pos:    start,
+	}
+	node, err = p.parseExpression(precedence["+"])
+	end = p.pos
+	return
+}
+
+// ParseAssignment parses "target = expression".
+func ParseAssignment(source string) (target string, node Node, err error) {
+	p, err := newParser(source)
+	if err != nil {
+		return
+	}
+	token := p.next()
+	if token.Type != TokenIdent {
+		err = newParseError(token.Pos, "expected column name, found %s", token)
+		return
+	}
+	target = token.Text
+	_, err = p.expect(TokenOperator, "=")
+	if err != nil {
+		return
+	}
+	node, err = p.parseExpression(0)
+	if err != nil {
+		return
+	}
+	err = p.expectEOF()
+	return
+}
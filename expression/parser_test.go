@@ -0,0 +1,71 @@
+package expression
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		source string
+		want   string
+	}{
+		{"a + b * 2", "(`a` + (`b` * 2))"},
+		{"(revenue - cost) / revenue", "((`revenue` - `cost`) / `revenue`)"},
+		{"-x + -1.5", "(-`x` + -1.5)"},
+		{"a > 1 && b < 2 || !c", "(((`a` > 1) && (`b` < 2)) || !`c`)"},
+		{"if(`unit price` >= 10, round(x, 2), abs(y))", "if((`unit price` >= 10), round(`x`, 2), abs(`y`))"},
+		{"name = 'DE'", "(`name` = \"DE\")"},
+	}
+	for _, c := range cases {
+		node, err := Parse(c.source)
+		if err != nil {
+			t.Errorf("%s: %v", c.source, err)
+			continue
+		}
+		if node.String() != c.want {
+			t.Errorf("%s: got %s, want %s", c.source, node, c.want)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	cases := []struct {
+		source string
+		pos    int
+	}{
+		{"a + ", 4},
+		{"(a + b", 6},
+		{"a $ b", 2},
+		{"a b", 2},
+		{"'abc", 0},
+	}
+	for _, c := range cases {
+		_, err := Parse(c.source)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: expected parse error, got %v", c.source, err)
+			continue
+		}
+		if parseErr.Pos != c.pos {
+			t.Errorf("%s: got position %d, want %d", c.source, parseErr.Pos, c.pos)
+		}
+	}
+}
+
+func TestParseAssignment(t *testing.T) {
+	target, node, err := ParseAssignment("margin = (revenue - cost) / revenue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target != "margin" {
+		t.Errorf("got target %s, want margin", target)
+	}
+	columns := Columns(node)
+	if len(columns) != 2 || columns[0] != "revenue" || columns[1] != "cost" {
+		t.Errorf("unexpected columns %v", columns)
+	}
+	if _, _, err := ParseAssignment("margin + 1"); err == nil {
+		t.Error("missing assignment should fail")
+	}
+}
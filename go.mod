@@ -2,4 +2,4 @@ module github.com/hunknownz/godas
 
 go 1.13
 
-require github.com/spf13/cast v1.3.1 // indirect
+require github.com/spf13/cast v1.3.1
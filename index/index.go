@@ -15,4 +15,56 @@ func (indexBool IndexBool) And(otherIndexBool IndexBool) {
 	for i := 0; i < indexLen; i++ {
 		indexBool[i] = indexBool[i] && otherIndexBool[i]
 	}
-}
\ No newline at end of file
+}
+
+func (indexBool IndexBool) Not() {
+	indexLen := len(indexBool)
+	for i := 0; i < indexLen; i++ {
+		indexBool[i] = !indexBool[i]
+	}
+}
+
+// IndexInt returns the positions which are true, so a boolean mask can be
+// passed to the Subset methods.
+func (indexBool IndexBool) IndexInt() IndexInt {
+	indexInt := make(IndexInt, 0)
+	for i, value := range indexBool {
+		if value {
+			indexInt = append(indexInt, uint32(i))
+		}
+	}
+	return indexInt
+}
+
+// AndKleene sets indexBool to indexBool && other in three-valued logic,
+// where unknown and otherUnknown mark the unknown positions, which are
+// false. unknown is updated in place.
+func (indexBool IndexBool) AndKleene(unknown, other, otherUnknown IndexBool) {
+	for i := range indexBool {
+		if (!indexBool[i] && !unknown[i]) || (!other[i] && !otherUnknown[i]) {
+			indexBool[i], unknown[i] = false, false
+			continue
+		}
+		indexBool[i] = indexBool[i] && other[i]
+		unknown[i] = unknown[i] || otherUnknown[i]
+	}
+}
+
+// OrKleene sets indexBool to indexBool || other in three-valued logic.
+func (indexBool IndexBool) OrKleene(unknown, other, otherUnknown IndexBool) {
+	for i := range indexBool {
+		if indexBool[i] || other[i] {
+			indexBool[i], unknown[i] = true, false
+			continue
+		}
+		unknown[i] = unknown[i] || otherUnknown[i]
+	}
+}
+
+// NotKleene negates indexBool in three-valued logic, unknown positions
+// staying unknown.
+func (indexBool IndexBool) NotKleene(unknown IndexBool) {
+	for i := range indexBool {
+		indexBool[i] = !indexBool[i] && !unknown[i]
+	}
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var iso8601Duration = regexp.MustCompile(`^([-+])?P(?:([\d.,]+)Y)?(?:([\d.,]+)M)?(?:([\d.,]+)W)?(?:([\d.,]+)D)?` +
+	`(?:T(?:([\d.,]+)H)?(?:([\d.,]+)M)?(?:([\d.,]+)S)?)?$`)
+
+// ParseDuration parses a Go duration string such as "1h30m", or an ISO 8601
+// duration such as "P1DT2H" or "PT0.5S". ISO 8601 years and months are
+// rejected since their length varies, days are 24 hours.
+func ParseDuration(s string) (d time.Duration, err error) {
+	match := iso8601Duration.FindStringSubmatch(s)
+	if match == nil {
+		d, err = time.ParseDuration(s)
+		return
+	}
+	if match[2] != "" || match[3] != "" {
+		err = errors.New(fmt.Sprintf("duration %q: years and months are not supported", s))
+		return
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	found := false
+	for i, unit := range units {
+		component := strings.Replace(match[i+4], ",", ".", 1)
+		if component == "" {
+			continue
+		}
+		found = true
+		if unit == time.Second {
+			// Parse the seconds exactly, down to the nanosecond.
+			seconds, e := time.ParseDuration(component + "s")
+			if e != nil {
+				err = errors.New(fmt.Sprintf("invalid duration %q", s))
+				return
+			}
+			if d, err = addDuration(s, d, seconds); err != nil {
+				return
+			}
+			continue
+		}
+		value, e := strconv.ParseFloat(component, 64)
+		if e != nil {
+			err = errors.New(fmt.Sprintf("invalid duration %q", s))
+			return
+		}
+		// float64(math.MaxInt64) is 2^63, the first value out of range.
+		nanos := value * float64(unit)
+		if nanos >= float64(math.MaxInt64) {
+			err = errors.New(fmt.Sprintf("duration %q overflows", s))
+			return
+		}
+		if d, err = addDuration(s, d, time.Duration(nanos)); err != nil {
+			return
+		}
+	}
+	if !found {
+		err = errors.New(fmt.Sprintf("invalid duration %q", s))
+		return
+	}
+	timeFound := match[6] != "" || match[7] != "" || match[8] != ""
+	if strings.Contains(s, "T") && !timeFound {
+		err = errors.New(fmt.Sprintf("invalid duration %q: T without time components", s))
+		return
+	}
+	if match[1] == "-" {
+		d = -d
+	}
+	return
+}
+
+// addDuration adds the non-negative component c of the duration s to d,
+// failing when the sum overflows.
+func addDuration(s string, d, c time.Duration) (sum time.Duration, err error) {
+	if d > math.MaxInt64-c {
+		err = errors.New(fmt.Sprintf("duration %q overflows", s))
+		return
+	}
+	sum = d + c
+	return
+}
@@ -7,12 +7,16 @@ import (
 	"github.com/hunknownz/godas/index"
 	"github.com/hunknownz/godas/types"
 	"log"
+	"time"
 )
 
+// ElementValue is an element read from Elements. IsNaN marks a missing
+// element, which is either null or a float NaN, and IsNull a null one.
 type ElementValue struct {
 	Value interface{}
 	Type types.Type
 	IsNaN bool
+	IsNull bool
 
 	Err error
 }
@@ -45,6 +49,20 @@ func (element ElementValue) Float() (float64, error) {
 	return float64(0), errors.New("type assertion to float failed")
 }
 
+func (element ElementValue) Time() (time.Time, error) {
+	if s, ok := (element.Value).(time.Time); ok {
+		return s, nil
+	}
+	return time.Time{}, errors.New("type assertion to time failed")
+}
+
+func (element ElementValue) Duration() (time.Duration, error) {
+	if s, ok := (element.Value).(time.Duration); ok {
+		return s, nil
+	}
+	return time.Duration(0), errors.New("type assertion to duration failed")
+}
+
 func (element ElementValue) Interface() (interface{}, error) {
 	if s, ok := (element.Value).(interface{}); ok {
 		return s, nil
@@ -52,6 +70,14 @@ func (element ElementValue) Interface() (interface{}, error) {
 	return interface{}(nil), errors.New("type assertion to object faile")
 }
 
+func (element ElementValue) NaN() bool {
+	return element.IsNaN
+}
+
+func (element ElementValue) Null() bool {
+	return element.IsNull
+}
+
 func (element ElementValue) MustBool(args ...bool) bool {
 	var def bool
 
@@ -128,6 +154,44 @@ func (element ElementValue) MustFloat(args ...float64) float64 {
 	return def
 }
 
+func (element ElementValue) MustTime(args ...time.Time) time.Time {
+	var def time.Time
+
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustTime() received too many arguments %d", len(args))
+	}
+
+	s, err := element.Time()
+	if err == nil {
+		return s
+	}
+
+	return def
+}
+
+func (element ElementValue) MustDuration(args ...time.Duration) time.Duration {
+	var def time.Duration
+
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustDuration() received too many arguments %d", len(args))
+	}
+
+	s, err := element.Duration()
+	if err == nil {
+		return s
+	}
+
+	return def
+}
+
 func (element ElementValue) MustInterface(args ...interface{}) interface{} {
 	var def interface{}
 
@@ -147,10 +211,35 @@ func (element ElementValue) MustInterface(args ...interface{}) interface{} {
 	return def
 }
 
+// Compare reports whether the element satisfies cond, unknown comparisons
+// with a missing element being false.
 func (element ElementValue) Compare(cond *condition.CondValue) (result bool, err error) {
+	result, _, err = element.CompareKleene(cond, condition.MissingUnknown)
+	return
+}
+
+// CompareKleene compares the element with cond in three-valued logic,
+// comparisons with a missing element following the missing policy missing.
+func (element ElementValue) CompareKleene(cond *condition.CondValue, missing int) (result, unknown bool, err error) {
 	if cond.Cond != nil {
 		expr := cond.Cond.Prepare()
-		result = element.EvaluateCondition(expr)
+		result, unknown = element.EvaluateKleene(expr, cond.Cond.InheritMissing(missing))
+		err = element.Err
+	} else if condition.IsNaNComparator(cond.CompItem.Comparator) {
+		result, err = cond.CompareNaN(element.IsNaN)
+	} else if cond.CompItem.Comparator == condition.ComparatorFunc {
+		f, ok := cond.CompItem.Value.(condition.ElementFunc)
+		if !ok || f == nil {
+			err = errors.New("compare error: func value must be a non-nil condition.ElementFunc")
+		} else {
+			result = f(element)
+		}
+	} else if cond.CompItem.Comparator == condition.ComparatorRowFunc {
+		err = errors.New("compare error: row functions are only supported in dataframe conditions")
+	} else if ref, ok := cond.CompItem.Value.(condition.ColumnRef); ok {
+		err = errors.New(fmt.Sprintf("compare error: column reference %s is only supported in dataframe conditions", ref))
+	} else if element.IsNaN {
+		result, unknown = condition.CompareMissing(missing)
 	} else {
 		switch element.Type {
 		case types.TypeInt:
@@ -166,7 +255,15 @@ func (element ElementValue) Compare(cond *condition.CondValue) (result bool, err
 			var e error
 			result, e = cond.CompareFloat64(leftVal)
 			if e != nil {
-				err = fmt.Errorf("compare error: %s", e)
+				err = fmt.Errorf("compare error: %w", e)
+				return
+			}
+		case types.TypeString:
+			leftVal := element.Value.(string)
+			var e error
+			result, e = cond.CompareString(leftVal)
+			if e != nil {
+				err = fmt.Errorf("compare error: %w", e)
 				return
 			}
 		case types.TypeBool:
@@ -174,43 +271,79 @@ func (element ElementValue) Compare(cond *condition.CondValue) (result bool, err
 			var e error
 			result, e = cond.CompareBool(leftVal)
 			if e != nil {
-				err = fmt.Errorf("compare error: %s", e)
+				err = fmt.Errorf("compare error: %w", e)
 				return
 			}
+		case types.TypeDatetime:
+			leftVal := element.Value.(time.Time)
+			var e error
+			result, e = cond.CompareTime(leftVal)
+			if e != nil {
+				err = fmt.Errorf("compare error: %w", e)
+				return
+			}
+		case types.TypeDuration:
+			leftVal := element.Value.(time.Duration)
+			var e error
+			result, e = cond.CompareDuration(leftVal)
+			if e != nil {
+				err = fmt.Errorf("compare error: %w", e)
+				return
+			}
+		case types.TypeObject:
+			var e error
+			result, e = cond.CompareObject(element.Value)
+			if e != nil {
+				err = fmt.Errorf("compare error: %w", e)
+				return
+			}
+		default:
+			err = errors.New(fmt.Sprintf("compare error: type %s is not supported", element.Type))
 		}
 	}
+	if cond.IsNot {
+		result, unknown = condition.KleeneNot(result, unknown)
+	}
 	return
 }
 
-func (element ElementValue) EvaluateCondition(expr condition.ExprAST) bool {
-	var l, r bool
+// EvaluateCondition reports whether the element satisfies expr, which only
+// holds when expr is true and not unknown.
+func (element *ElementValue) EvaluateCondition(expr condition.ExprAST) bool {
+	result, _ := element.EvaluateKleene(expr, condition.MissingUnknown)
+	return result
+}
+
+// EvaluateKleene evaluates expr for the element in three-valued logic,
+// comparisons with a missing element following the missing policy missing.
+func (element *ElementValue) EvaluateKleene(expr condition.ExprAST, missing int) (result, unknown bool) {
 	switch expr.(type) {
 	case condition.BinaryExprAST:
 		ast := expr.(condition.BinaryExprAST)
-		l = element.EvaluateCondition(ast.Lhs)
-		if ast.Op == "&&" && !l {
-			return false
+		l, lUnknown := element.EvaluateKleene(ast.Lhs, missing)
+		if ast.Op == "&&" && !l && !lUnknown {
+			return false, false
 		}
 		if ast.Op == "||" && l {
-			return true
+			return true, false
 		}
-		r = element.EvaluateCondition(ast.Rhs)
+		r, rUnknown := element.EvaluateKleene(ast.Rhs, missing)
 		switch ast.Op {
 		case "&&":
-			return l && r
+			return condition.KleeneAnd(l, lUnknown, r, rUnknown)
 		case "||":
-			return l || r
+			return condition.KleeneOr(l, lUnknown, r, rUnknown)
 		}
 	case condition.ValueExprAST:
 		cond := expr.(condition.ValueExprAST).Value
-		result, err := element.Compare(cond)
+		result, unknown, err := element.CompareKleene(cond, missing)
 		if err != nil {
 			element.Err = err
-			return false
+			return false, false
 		}
-		return result
+		return result, unknown
 	}
-	return true
+	return true, false
 }
 
 type Elements interface {
@@ -220,6 +353,7 @@ type Elements interface {
 	Copy() (newElements Elements)
 	Subset(index.IndexInt) (newElements Elements, err error)
 	IsNaN() []bool
+	IsNull() []bool
 	Location(int) (ElementValue, error)
 	Swap(i, j int)
 	Append(copy bool, values ...interface{}) (newElements Elements, err error)
@@ -0,0 +1,119 @@
+package elements
+
+import (
+	"github.com/hunknownz/godas/index"
+	"strings"
+)
+
+// Validity is the validity bitmap of elements, bit i being set when element
+// i holds a value and clear when it's null. A nil Validity marks every
+// element valid, so elements without nulls don't carry a bitmap.
+type Validity []uint64
+
+// NewValidity returns the validity bitmap of the null mask isNull, nil when
+// no element is null.
+func NewValidity(isNull []bool) (validity Validity) {
+	return validity.Append(0, isNull...)
+}
+
+func validityWords(n int) int {
+	return (n + 63) >> 6
+}
+
+func (validity Validity) IsValid(i int) bool {
+	return validity == nil || validity[i>>6]&(1<<uint(i&63)) != 0
+}
+
+func (validity Validity) set(i int, valid bool) {
+	if valid {
+		validity[i>>6] |= 1 << uint(i&63)
+	} else {
+		validity[i>>6] &^= 1 << uint(i&63)
+	}
+}
+
+// IsNull returns the null mask of the first n elements.
+func (validity Validity) IsNull(n int) []bool {
+	isNull := make([]bool, n)
+	if validity == nil {
+		return isNull
+	}
+	for i := range isNull {
+		isNull[i] = !validity.IsValid(i)
+	}
+	return isNull
+}
+
+func (validity Validity) Copy() Validity {
+	if validity == nil {
+		return nil
+	}
+	newValidity := make(Validity, len(validity))
+	copy(newValidity, validity)
+	return newValidity
+}
+
+// Subset returns the validity of the elements at idx.
+func (validity Validity) Subset(idx index.IndexInt) Validity {
+	if validity == nil {
+		return nil
+	}
+	newValidity := make(Validity, validityWords(len(idx)))
+	for newI, i := range idx {
+		newValidity.set(newI, validity.IsValid(int(i)))
+	}
+	return newValidity
+}
+
+func (validity Validity) Swap(i, j int) {
+	if validity == nil {
+		return
+	}
+	iValid, jValid := validity.IsValid(i), validity.IsValid(j)
+	validity.set(i, jValid)
+	validity.set(j, iValid)
+}
+
+// Append returns the validity of n elements followed by elements with the
+// null mask isNull. The bitmap is only allocated once a null is appended.
+func (validity Validity) Append(n int, isNull ...bool) Validity {
+	if validity == nil {
+		hasNull := false
+		for _, null := range isNull {
+			hasNull = hasNull || null
+		}
+		if !hasNull {
+			return nil
+		}
+		validity = make(Validity, validityWords(n))
+		for i := 0; i < n; i++ {
+			validity.set(i, true)
+		}
+	}
+	for len(validity) < validityWords(n+len(isNull)) {
+		validity = append(validity, 0)
+	}
+	for i, null := range isNull {
+		validity.set(n+i, !null)
+	}
+	return validity
+}
+
+// Format formats n elements like fmt.Sprint formats a slice, printing null
+// elements as null.
+func (validity Validity) Format(n int, format func(i int) string) string {
+	var builder strings.Builder
+	builder.WriteString("[")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			builder.WriteString(" ")
+		}
+		if validity.IsValid(i) {
+			builder.WriteString(format(i))
+		} else {
+			builder.WriteString("null")
+		}
+	}
+	builder.WriteString("]")
+	return builder.String()
+}
@@ -24,4 +24,16 @@ func NewElementsBool(elements []bool) (newElements ElementsBool) {
 
 	newElements = newBitBools
 	return
-}
\ No newline at end of file
+}
+
+// NewNullableElementsBool returns elements whose elements are null where
+// isNull is set, whatever their value.
+func NewNullableElementsBool(elements []bool, isNull []bool) (newElements ElementsBool) {
+	newElements = NewElementsBool(elements)
+	for bitsI, null := range isNull {
+		if null {
+			newElements.set(bitsI, nanValue)
+		}
+	}
+	return
+}
@@ -10,6 +10,8 @@ import (
 	"reflect"
 )
 
+// ElementsBool packs bools two bits each, which also encode their validity:
+// a null element has the nan bit value.
 type ElementsBool = BitBools
 
 func (elements ElementsBool) Type() (sType types.Type) {
@@ -21,7 +23,19 @@ func (elements ElementsBool) Len() int {
 }
 
 func (elements ElementsBool) String() string {
-	return fmt.Sprint(elements)
+	values := make([]string, elements.Len())
+	for i := range values {
+		value, _ := elements.location(i)
+		switch value {
+		case nanValue:
+			values[i] = "null"
+		case trueValue:
+			values[i] = "true"
+		default:
+			values[i] = "false"
+		}
+	}
+	return fmt.Sprint(values)
 }
 
 func (elements ElementsBool) Copy() (newElements elements.Elements) {
@@ -82,6 +96,12 @@ func (elements ElementsBool) IsNaN() []bool {
 	}
 	return nanElements
 }
+
+// IsNull is IsNaN, bools having no NaN value.
+func (elements ElementsBool) IsNull() []bool {
+	return elements.IsNaN()
+}
+
 func (elements ElementsBool) Location(coord int) (element elements.ElementValue, err error) {
 	val, err := elements.location(coord)
 	if err != nil {
@@ -89,6 +109,7 @@ func (elements ElementsBool) Location(coord int) (element elements.ElementValue,
 	}
 	element.Type = types.TypeBool
 	element.IsNaN = val == nanValue
+	element.IsNull = element.IsNaN
 	element.Value = val == trueValue
 	return
 }
@@ -132,6 +153,9 @@ func (elements ElementsBool) location(coord int) (value bitBoolValue, err error)
 }
 
 func (elements ElementsBool) bitBoolsLen() int {
+	if elements.bitsSliceLen == 0 {
+		return 0
+	}
 	i := elements.bitsSliceLen - 1
 	preLen := int(i << 4)
 	lastChunk := elements.bits[i]
@@ -184,6 +208,7 @@ func (elements ElementsBool) Swap(i, j int) {
 	elements.set(j, aValue)
 }
 
+// Append appends bool values, nil appending a null element.
 func (elements ElementsBool) Append(copy bool, values ...interface{}) (newElements elements.Elements, err error) {
 	var nElements ElementsBool
 	if !copy {
@@ -193,6 +218,9 @@ func (elements ElementsBool) Append(copy bool, values ...interface{}) (newElemen
 	}
 
 	for _, value := range values {
+		if value == nil {
+			continue
+		}
 		kind := reflect.TypeOf(value).Kind()
 		if kind != reflect.Bool {
 			err = errors.New(fmt.Sprintf("bool elements can't append %s", kind.String()))
@@ -217,6 +245,10 @@ func (elements ElementsBool) Append(copy bool, values ...interface{}) (newElemen
 	}
 
 	for i, value := range values {
+		if value == nil {
+			nElements.set(i+oldElementsLen, nanValue)
+			continue
+		}
 		bValue := value.(bool)
 		boolValue := internal.If(bValue == true, trueValue, falseValue)
 		nElements.set(i+oldElementsLen, boolValue.(bitBoolValue))
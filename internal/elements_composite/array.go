@@ -5,14 +5,18 @@ import (
 	"fmt"
 	"github.com/hunknownz/godas/condition"
 	"github.com/hunknownz/godas/index"
+	"github.com/hunknownz/godas/internal"
 	"github.com/hunknownz/godas/internal/elements"
 	sbool "github.com/hunknownz/godas/internal/elements_bool"
+	sdatetime "github.com/hunknownz/godas/internal/elements_datetime"
+	sduration "github.com/hunknownz/godas/internal/elements_duration"
 	sfloat "github.com/hunknownz/godas/internal/elements_float"
 	sint "github.com/hunknownz/godas/internal/elements_int"
 	sobject "github.com/hunknownz/godas/internal/elements_object"
 	sstring "github.com/hunknownz/godas/internal/elements_string"
 	"github.com/hunknownz/godas/types"
 	"reflect"
+	"time"
 )
 
 type Array struct {
@@ -53,9 +57,61 @@ func (array *Array) IsNaN() []bool {
 	return array.Elements.IsNaN()
 }
 
+func (array *Array) IsNull() []bool {
+	return array.Elements.IsNull()
+}
+
+// IsCondition compiles cond once into kernels over the typed elements and
+// evaluates it for every element. Only the elements for which cond is true,
+// not unknown, are selected.
 func (array *Array) IsCondition(cond *condition.Condition) (ixs index.IndexBool, err error) {
+	return array.IsConditionParallel(cond, 1)
+}
+
+// IsConditionParallel is IsCondition splitting the elements into row ranges
+// evaluated on up to workers goroutines.
+func (array *Array) IsConditionParallel(cond *condition.Condition, workers int) (ixs index.IndexBool, err error) {
+	ixs, _, err = array.IsConditionKleene(cond, workers)
+	return
+}
+
+// IsConditionKleene is IsConditionParallel also returning which elements
+// have an unknown result in three-valued logic.
+func (array *Array) IsConditionKleene(cond *condition.Condition, workers int) (ixs, unknown index.IndexBool, err error) {
+	compiler := &conditionCompiler{
+		array: array,
+	}
+	k := compiler.compile(cond.Prepare(), cond.Missing())
+	n := array.Elements.Len()
+	bits := make(bitmap, bitmapWords(n))
+	unknownBits := make(bitmap, len(bits))
+	// Ranges are split on words, so that no two workers write the same word.
+	err = internal.ParallelRange(workers, len(bits), func(startWord, endWord int) error {
+		start, end := startWord<<6, endWord<<6
+		if end > n {
+			end = n
+		}
+		return k(bits[startWord:endWord], unknownBits[startWord:endWord], start, end-start)
+	})
+	if err != nil {
+		err = fmt.Errorf("is condition error: %w", err)
+		return
+	}
+	ixs = make(index.IndexBool, n)
+	unknown = make(index.IndexBool, n)
+	for i := range ixs {
+		ixs[i], unknown[i] = bits.get(i), unknownBits.get(i)
+	}
+	return
+}
+
+// isConditionByElement evaluates the whole condition element by element. It
+// is the reference the kernels of IsCondition are tested and benchmarked
+// against.
+func (array *Array) isConditionByElement(cond *condition.Condition) (ixs index.IndexBool, err error) {
 	expr := cond.Prepare()
 	seLen := array.Elements.Len()
+	isNaN := array.Elements.IsNaN()
 	ixs = make(index.IndexBool, seLen)
 	for i := 0; i < seLen; i++ {
 		element, e := array.Elements.Location(i)
@@ -63,7 +119,8 @@ func (array *Array) IsCondition(cond *condition.Condition) (ixs index.IndexBool,
 			err = fmt.Errorf("is condition error: %w", e)
 			return
 		}
-		ixs[i] = element.EvaluateCondition(expr)
+		element.IsNaN = isNaN[i]
+		ixs[i], _ = element.EvaluateKleene(expr, cond.Missing())
 		if element.Err != nil {
 			err = element.Err
 			return
@@ -101,13 +158,14 @@ func (array *Array) Swap(i, j int) {
 }
 
 func (array *Array) Append(copy bool, values ...interface{}) (newArray *Array, err error) {
-	newElements, err := array.Elements.Append(copy, values)
+	newElements, err := array.Elements.Append(copy, values...)
 	if err != nil {
 		err = fmt.Errorf("append array error: %w", err)
 		return
 	}
 
 	if !copy {
+		array.Elements = newElements
 		newArray = array
 	} else {
 		newArray = &Array{
@@ -118,6 +176,9 @@ func (array *Array) Append(copy bool, values ...interface{}) (newArray *Array, e
 	return
 }
 
+// NewArray returns an array of values, a slice of ints, floats, strings,
+// bools, times, durations or objects. Slices of pointers to ints, floats,
+// strings, bools, times or durations hold nulls as nil pointers.
 func NewArray(values interface{}, fieldName string) (array *Array, err error) {
 	array = new(Array)
 	switch values.(type) {
@@ -159,6 +220,104 @@ func NewArray(values interface{}, fieldName string) (array *Array, err error) {
 		vals := values.([]interface{})
 		newElements := sobject.NewElementsObject(vals)
 		array.Elements = newElements
+	case []*int:
+		vals := values.([]*int)
+		vals64 := make([]int64, len(vals))
+		isNull := make([]bool, len(vals))
+		for i, val := range vals {
+			if val == nil {
+				isNull[i] = true
+				continue
+			}
+			vals64[i] = int64(*val)
+		}
+		array.Elements = sint.NewNullableElementsInt64(vals64, isNull)
+	case []*int64:
+		vals := values.([]*int64)
+		vals64 := make([]int64, len(vals))
+		isNull := make([]bool, len(vals))
+		for i, val := range vals {
+			if val == nil {
+				isNull[i] = true
+				continue
+			}
+			vals64[i] = *val
+		}
+		array.Elements = sint.NewNullableElementsInt64(vals64, isNull)
+	case []*float64:
+		vals := values.([]*float64)
+		vals64 := make([]float64, len(vals))
+		isNull := make([]bool, len(vals))
+		for i, val := range vals {
+			if val == nil {
+				isNull[i] = true
+				continue
+			}
+			vals64[i] = *val
+		}
+		array.Elements = sfloat.NewNullableElementsFloat64(vals64, isNull)
+	case []*string:
+		vals := values.([]*string)
+		strs := make([]string, len(vals))
+		isNull := make([]bool, len(vals))
+		for i, val := range vals {
+			if val == nil {
+				isNull[i] = true
+				continue
+			}
+			strs[i] = *val
+		}
+		array.Elements = sstring.NewNullableElementsString(strs, isNull)
+	case []*bool:
+		vals := values.([]*bool)
+		bools := make([]bool, len(vals))
+		isNull := make([]bool, len(vals))
+		for i, val := range vals {
+			if val == nil {
+				isNull[i] = true
+				continue
+			}
+			bools[i] = *val
+		}
+		array.Elements = sbool.NewNullableElementsBool(bools, isNull)
+	case []time.Time:
+		vals := values.([]time.Time)
+		array.Elements = sdatetime.NewElementsTime(vals)
+	case []*time.Time:
+		vals := values.([]*time.Time)
+		nanos := make([]int64, len(vals))
+		isNull := make([]bool, len(vals))
+		var location *time.Location
+		for i, val := range vals {
+			if val == nil {
+				isNull[i] = true
+				continue
+			}
+			if location == nil {
+				location = val.Location()
+			}
+			nanos[i] = val.UnixNano()
+		}
+		array.Elements = sdatetime.NewElementsDatetime(nanos, isNull, location)
+	case []time.Duration:
+		vals := values.([]time.Duration)
+		nanos := make([]int64, len(vals))
+		for i, val := range vals {
+			nanos[i] = int64(val)
+		}
+		array.Elements = sduration.NewElementsDuration(nanos, nil)
+	case []*time.Duration:
+		vals := values.([]*time.Duration)
+		nanos := make([]int64, len(vals))
+		isNull := make([]bool, len(vals))
+		for i, val := range vals {
+			if val == nil {
+				isNull[i] = true
+				continue
+			}
+			nanos[i] = int64(*val)
+		}
+		array.Elements = sduration.NewElementsDuration(nanos, isNull)
 	default:
 		typ := reflect.TypeOf(values).Kind().String()
 		err = errors.New(fmt.Sprintf("new series errors: type %s is not supported", typ))
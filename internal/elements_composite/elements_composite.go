@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"github.com/hunknownz/godas/index"
+	"github.com/hunknownz/godas/internal"
 	"github.com/hunknownz/godas/internal/elements"
 	"github.com/hunknownz/godas/types"
 	"reflect"
 	"strconv"
+	"time"
 )
 
 type ElementsComposite struct {
@@ -21,7 +23,7 @@ func (els *ElementsComposite) Type() types.Type {
 }
 
 func (els *ElementsComposite) String() string {
-	return fmt.Sprint(els)
+	return fmt.Sprint(els.Fields)
 }
 
 func (els *ElementsComposite) Len() int {
@@ -102,15 +104,21 @@ func (els *ElementsComposite) numColumn() int {
 }
 
 func (els *ElementsComposite) Subset(index index.IndexInt) (newElements elements.Elements, err error) {
+	return els.SubsetParallel(index, 1)
+}
+
+// SubsetParallel is Subset taking the subsets of the columns on up to
+// workers goroutines.
+func (els *ElementsComposite) SubsetParallel(index index.IndexInt, workers int) (newElements elements.Elements, err error) {
 	columnNum := els.numColumn()
 	arrays := make([]*Array, columnNum)
-	for i, array := range els.NArray {
-		newArray, e := array.Subset(index)
-		if e != nil {
-			err = fmt.Errorf("sbuset dataframe error: %w", e)
-			return
-		}
-		arrays[i] = newArray
+	err = internal.ParallelEach(workers, len(els.NArray), func(i int) (e error) {
+		arrays[i], e = els.NArray[i].Subset(index)
+		return
+	})
+	if err != nil {
+		err = fmt.Errorf("sbuset dataframe error: %w", err)
+		return
 	}
 
 	_, colNum, err := checkColumnsLengths(arrays...)
@@ -149,6 +157,20 @@ func (els ElementsComposite) IsNaN() (result []bool) {
 	return
 }
 
+func (els ElementsComposite) IsNull() (result []bool) {
+	columnNum := els.numColumn()
+	if columnNum == 0 {
+		return
+	}
+	l := index.IndexBool(els.NArray[0].IsNull())
+	for i := 1; i < columnNum; i++ {
+		l.And(els.NArray[i].IsNull())
+	}
+
+	result = l
+	return
+}
+
 func (els *ElementsComposite) Location(coord int) (element elements.ElementValue, err error) {
 	if coord < 0 {
 		err = errors.New(fmt.Sprintf("invalid index %d (index must be non-negative)", coord))
@@ -216,6 +238,16 @@ func (els *ElementsComposite) checkAppendMapLikeRecords(values map[string]interf
 				err = errors.New(fmt.Sprintf("can't append object value to %s array", typ))
 				return
 			}
+		case []time.Time:
+			if typ != types.TypeDatetime {
+				err = errors.New(fmt.Sprintf("can't append time value to %s array", typ))
+				return
+			}
+		case []time.Duration:
+			if typ != types.TypeDuration {
+				err = errors.New(fmt.Sprintf("can't append duration value to %s array", typ))
+				return
+			}
 		default:
 			valueType := reflect.TypeOf(value).Kind().String()
 			err = errors.New(fmt.Sprintf("type %s is not supported in this composite", valueType))
@@ -276,6 +308,16 @@ func (els *ElementsComposite) appendMapLikeObject(values map[string]interface{})
 			for _, v := range val {
 				array.Append(false, v)
 			}
+		case []time.Time:
+			val := value.([]time.Time)
+			for _, v := range val {
+				array.Append(false, v)
+			}
+		case []time.Duration:
+			val := value.([]time.Duration)
+			for _, v := range val {
+				array.Append(false, v)
+			}
 		}
 	}
 	return
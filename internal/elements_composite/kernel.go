@@ -0,0 +1,526 @@
+package elements_composite
+
+import (
+	"github.com/hunknownz/godas/condition"
+	"github.com/hunknownz/godas/internal/elements"
+	sfloat "github.com/hunknownz/godas/internal/elements_float"
+	sint "github.com/hunknownz/godas/internal/elements_int"
+	sstring "github.com/hunknownz/godas/internal/elements_string"
+	"math"
+	"strings"
+)
+
+// bitmap holds one bit per row of a row range, bit i standing for row
+// start+i, laid out like elements.Validity. Bits past the end of the range
+// are unspecified.
+type bitmap []uint64
+
+func bitmapWords(n int) int {
+	return (n + 63) >> 6
+}
+
+func (b bitmap) get(i int) bool {
+	return b[i>>6]&(1<<uint(i&63)) != 0
+}
+
+func (b bitmap) set(i int) {
+	b[i>>6] |= 1 << uint(i&63)
+}
+
+// andKleene sets b to b && other in three-valued logic, where unknown and
+// otherUnknown mark the unknown rows, which are clear in b and other. It
+// works a word of 64 rows at a time.
+func (b bitmap) andKleene(unknown, other, otherUnknown bitmap) {
+	for w := range b {
+		isFalse := (^b[w] &^ unknown[w]) | (^other[w] &^ otherUnknown[w])
+		b[w] &= other[w]
+		unknown[w] = (unknown[w] | otherUnknown[w]) &^ isFalse
+	}
+}
+
+// orKleene sets b to b || other in three-valued logic.
+func (b bitmap) orKleene(unknown, other, otherUnknown bitmap) {
+	for w := range b {
+		b[w] |= other[w]
+		unknown[w] = (unknown[w] | otherUnknown[w]) &^ b[w]
+	}
+}
+
+// notKleene negates b in three-valued logic, unknown rows staying unknown.
+func (b bitmap) notKleene(unknown bitmap) {
+	for w := range b {
+		b[w] = ^b[w] &^ unknown[w]
+	}
+}
+
+// kernel sets the bits of ixs for the n elements of an array from start on
+// for which a compiled condition is true, so that row ranges can be
+// evaluated concurrently. start is a multiple of 64 and both bitmaps are
+// clear on entry. Results are three-valued: unknown marks the elements whose
+// result is unknown, which are clear in ixs.
+type kernel func(ixs, unknown bitmap, start, n int) error
+
+// conditionCompiler compiles a condition once into kernels which scan the
+// typed elements of an array directly. Comparisons without a typed kernel
+// fall back to comparing element by element. Everything shared by the
+// kernels, such as the NaN mask, is computed while compiling, so kernels
+// only read it.
+type conditionCompiler struct {
+	array *Array
+	isNaN []bool
+}
+
+func (c *conditionCompiler) nanMask() []bool {
+	if c.isNaN == nil {
+		c.isNaN = c.array.Elements.IsNaN()
+	}
+	return c.isNaN
+}
+
+// compile compiles expr, whose comparisons with missing elements follow the
+// missing policy missing.
+func (c *conditionCompiler) compile(expr condition.ExprAST, missing int) (k kernel) {
+	switch expr.(type) {
+	case condition.BinaryExprAST:
+		ast := expr.(condition.BinaryExprAST)
+		lhs := c.compile(ast.Lhs, missing)
+		rhs := c.compile(ast.Rhs, missing)
+		isAnd := ast.Op == "&&"
+		k = func(ixs, unknown bitmap, start, n int) (err error) {
+			err = lhs(ixs, unknown, start, n)
+			if err != nil {
+				return
+			}
+			other := make(bitmap, len(ixs))
+			otherUnknown := make(bitmap, len(ixs))
+			err = rhs(other, otherUnknown, start, n)
+			if err != nil {
+				return
+			}
+			if isAnd {
+				ixs.andKleene(unknown, other, otherUnknown)
+			} else {
+				ixs.orKleene(unknown, other, otherUnknown)
+			}
+			return
+		}
+	case condition.ValueExprAST:
+		condVal := expr.(condition.ValueExprAST).Value
+		if condVal.Cond != nil {
+			k = c.compile(condVal.Cond.Prepare(), condVal.Cond.InheritMissing(missing))
+		} else {
+			k = c.compileCompItem(condVal, missing)
+		}
+		if condVal.IsNot {
+			inner := k
+			k = func(ixs, unknown bitmap, start, n int) (err error) {
+				err = inner(ixs, unknown, start, n)
+				ixs.notKleene(unknown)
+				return
+			}
+		}
+	default:
+		k = func(ixs, unknown bitmap, start, n int) error {
+			for w := range ixs {
+				ixs[w] = ^uint64(0)
+			}
+			return nil
+		}
+	}
+	return
+}
+
+func (c *conditionCompiler) compileCompItem(condVal *condition.CondValue, missing int) (k kernel) {
+	comparator := condVal.CompItem.Comparator
+	if condition.IsNaNComparator(comparator) {
+		isNaN := c.nanMask()
+		nanTrue, err := condVal.CompareNaN(true)
+		nanFalse, _ := condVal.CompareNaN(false)
+		return func(ixs, unknown bitmap, start, n int) error {
+			if err != nil {
+				return err
+			}
+			for i, elementIsNaN := range isNaN[start : start+n] {
+				if (elementIsNaN && nanTrue) || (!elementIsNaN && nanFalse) {
+					ixs.set(i)
+				}
+			}
+			return nil
+		}
+	}
+	if !condVal.ComparesMissing() {
+		return c.elementKernel(condVal, missing)
+	}
+
+	switch values := c.array.Elements.(type) {
+	case sint.ElementsInt64:
+		k = int64Kernel(values, condVal)
+	case sfloat.ElementsFloat64:
+		k = float64Kernel(values, condVal)
+	case sstring.ElementsString:
+		k = stringKernel(values, condVal)
+	}
+	if k == nil {
+		k = c.elementKernel(condVal, missing)
+	} else if missing != condition.MissingUnknown {
+		k = resolveMissing(k, missing)
+	}
+	return
+}
+
+// resolveMissing resolves the unknown results of a typed kernel, which are
+// its comparisons with missing elements, with the missing policy missing.
+func resolveMissing(k kernel, missing int) kernel {
+	missingResult, _ := condition.CompareMissing(missing)
+	return func(ixs, unknown bitmap, start, n int) (err error) {
+		err = k(ixs, unknown, start, n)
+		for w := range unknown {
+			if missingResult {
+				ixs[w] |= unknown[w]
+			}
+			unknown[w] = 0
+		}
+		return
+	}
+}
+
+// elementKernel compares element by element through ElementValue.CompareKleene,
+// for the element types and comparisons without a typed kernel.
+func (c *conditionCompiler) elementKernel(condVal *condition.CondValue, missing int) kernel {
+	isNaN := c.nanMask()
+	elements := c.array.Elements
+	return func(ixs, unknown bitmap, start, n int) error {
+		for i := 0; i < n; i++ {
+			element, err := elements.Location(start + i)
+			if err != nil {
+				return err
+			}
+			element.IsNaN = isNaN[start+i]
+			result, isUnknown, err := element.CompareKleene(&condition.CondValue{
+				CompItem: condVal.CompItem,
+			}, missing)
+			if err != nil {
+				return err
+			}
+			if result {
+				ixs.set(i)
+			}
+			if isUnknown {
+				unknown.set(i)
+			}
+		}
+		return nil
+	}
+}
+
+func orderedInt64(comparator string, value int64) func(int64) bool {
+	switch comparator {
+	case condition.ComparatorGT:
+		return func(v int64) bool { return v > value }
+	case condition.ComparatorGTE:
+		return func(v int64) bool { return v >= value }
+	case condition.ComparatorEq:
+		return func(v int64) bool { return v == value }
+	case condition.ComparatorNotEq:
+		return func(v int64) bool { return v != value }
+	case condition.ComparatorLT:
+		return func(v int64) bool { return v < value }
+	case condition.ComparatorLTE:
+		return func(v int64) bool { return v <= value }
+	}
+	return nil
+}
+
+// orderedFloat64 follows IEEE 754, so a NaN value only satisfies !=.
+func orderedFloat64(comparator string, value float64) func(float64) bool {
+	switch comparator {
+	case condition.ComparatorGT:
+		return func(v float64) bool { return v > value }
+	case condition.ComparatorGTE:
+		return func(v float64) bool { return v >= value }
+	case condition.ComparatorEq:
+		return func(v float64) bool { return v == value }
+	case condition.ComparatorNotEq:
+		return func(v float64) bool { return v != value }
+	case condition.ComparatorLT:
+		return func(v float64) bool { return v < value }
+	case condition.ComparatorLTE:
+		return func(v float64) bool { return v <= value }
+	}
+	return nil
+}
+
+func orderedString(comparator string, value string) func(string) bool {
+	switch comparator {
+	case condition.ComparatorGT:
+		return func(v string) bool { return v > value }
+	case condition.ComparatorGTE:
+		return func(v string) bool { return v >= value }
+	case condition.ComparatorEq:
+		return func(v string) bool { return v == value }
+	case condition.ComparatorNotEq:
+		return func(v string) bool { return v != value }
+	case condition.ComparatorLT:
+		return func(v string) bool { return v < value }
+	case condition.ComparatorLTE:
+		return func(v string) bool { return v <= value }
+	}
+	return nil
+}
+
+// rangeComparators returns the comparators of the low and high bounds of r.
+func rangeComparators(r condition.Range) (low, high string) {
+	low, high = condition.ComparatorGT, condition.ComparatorLT
+	if r.IncludeLow {
+		low = condition.ComparatorGTE
+	}
+	if r.IncludeHigh {
+		high = condition.ComparatorLTE
+	}
+	return
+}
+
+func toFloat64(value interface{}) (floatValue float64, ok bool) {
+	switch value.(type) {
+	case int64:
+		return float64(value.(int64)), true
+	case float64:
+		return value.(float64), true
+	}
+	return
+}
+
+// float64Predicate compiles an ordered or between comparison with a
+// numeric value. It returns nil for any other comparison.
+func float64Predicate(item *condition.CompItem) func(float64) bool {
+	if item.Comparator == condition.ComparatorBetween {
+		r, ok := item.Value.(condition.Range)
+		if !ok {
+			return nil
+		}
+		low, lowOk := toFloat64(r.Low)
+		high, highOk := toFloat64(r.High)
+		if !lowOk || !highOk {
+			return nil
+		}
+		lowComparator, highComparator := rangeComparators(r)
+		lowPredicate := orderedFloat64(lowComparator, low)
+		highPredicate := orderedFloat64(highComparator, high)
+		return func(v float64) bool { return lowPredicate(v) && highPredicate(v) }
+	}
+	value, ok := toFloat64(item.Value)
+	if !ok {
+		return nil
+	}
+	return orderedFloat64(item.Comparator, value)
+}
+
+func int64Predicate(item *condition.CompItem) func(int64) bool {
+	switch item.Comparator {
+	case condition.ComparatorIn, condition.ComparatorNotIn:
+		values, ok := item.Value.([]interface{})
+		if !ok {
+			return nil
+		}
+		set := make(map[int64]struct{}, len(values))
+		for _, value := range values {
+			switch value.(type) {
+			case int64:
+				set[value.(int64)] = struct{}{}
+			case float64:
+				floatValue := value.(float64)
+				if floatValue == math.Trunc(floatValue) && math.Abs(floatValue) < math.MaxInt64 {
+					set[int64(floatValue)] = struct{}{}
+				}
+			}
+		}
+		in := item.Comparator == condition.ComparatorIn
+		return func(v int64) bool {
+			_, ok := set[v]
+			return ok == in
+		}
+	case condition.ComparatorBetween:
+		r, ok := item.Value.(condition.Range)
+		if !ok {
+			return nil
+		}
+		low, lowOk := r.Low.(int64)
+		high, highOk := r.High.(int64)
+		if !lowOk || !highOk {
+			break
+		}
+		lowComparator, highComparator := rangeComparators(r)
+		lowPredicate := orderedInt64(lowComparator, low)
+		highPredicate := orderedInt64(highComparator, high)
+		return func(v int64) bool { return lowPredicate(v) && highPredicate(v) }
+	default:
+		if value, ok := item.Value.(int64); ok {
+			return orderedInt64(item.Comparator, value)
+		}
+	}
+	predicate := float64Predicate(item)
+	if predicate == nil {
+		return nil
+	}
+	return func(v int64) bool { return predicate(float64(v)) }
+}
+
+// validityKernel marks the null elements from start on unknown and clears
+// their results, copying the validity bitmap a word at a time, since start
+// is a multiple of 64 too.
+func validityKernel(validity elements.Validity, ixs, unknown bitmap, start int) {
+	if validity == nil {
+		return
+	}
+	for w := range ixs {
+		valid := validity[start>>6+w]
+		ixs[w] &= valid
+		unknown[w] = ^valid
+	}
+}
+
+func int64Kernel(values sint.ElementsInt64, condVal *condition.CondValue) kernel {
+	predicate := int64Predicate(condVal.CompItem)
+	if predicate == nil {
+		return nil
+	}
+	validity := values.Validity()
+	return func(ixs, unknown bitmap, start, n int) error {
+		for i, v := range values.Values()[start : start+n] {
+			if predicate(v) {
+				ixs.set(i)
+			}
+		}
+		validityKernel(validity, ixs, unknown, start)
+		return nil
+	}
+}
+
+func float64Kernel(values sfloat.ElementsFloat64, condVal *condition.CondValue) kernel {
+	item := condVal.CompItem
+	var predicate func(float64) bool
+	switch item.Comparator {
+	case condition.ComparatorIn, condition.ComparatorNotIn:
+		setValues, ok := item.Value.([]interface{})
+		if !ok {
+			return nil
+		}
+		set := make(map[float64]struct{}, len(setValues))
+		for _, value := range setValues {
+			if floatValue, ok := toFloat64(value); ok {
+				set[floatValue] = struct{}{}
+			}
+		}
+		in := item.Comparator == condition.ComparatorIn
+		predicate = func(v float64) bool {
+			_, ok := set[v]
+			return ok == in
+		}
+	default:
+		predicate = float64Predicate(item)
+	}
+	if predicate == nil {
+		return nil
+	}
+	return func(ixs, unknown bitmap, start, n int) error {
+		for i, v := range values.Values()[start : start+n] {
+			if math.IsNaN(v) {
+				unknown.set(i)
+			} else if predicate(v) {
+				ixs.set(i)
+			}
+		}
+		return nil
+	}
+}
+
+func stringKernel(values sstring.ElementsString, condVal *condition.CondValue) kernel {
+	item := condVal.CompItem
+	var predicate func(string) bool
+	switch item.Comparator {
+	case condition.ComparatorIn, condition.ComparatorNotIn:
+		setValues, ok := item.Value.([]interface{})
+		if !ok {
+			return nil
+		}
+		set := make(map[string]struct{}, len(setValues))
+		for _, value := range setValues {
+			if stringValue, ok := value.(string); ok {
+				set[stringValue] = struct{}{}
+			}
+		}
+		in := item.Comparator == condition.ComparatorIn
+		predicate = func(v string) bool {
+			_, ok := set[v]
+			return ok == in
+		}
+	case condition.ComparatorBetween:
+		r, ok := item.Value.(condition.Range)
+		if !ok {
+			return nil
+		}
+		low, lowOk := r.Low.(string)
+		high, highOk := r.High.(string)
+		if !lowOk || !highOk {
+			return nil
+		}
+		lowComparator, highComparator := rangeComparators(r)
+		lowPredicate := orderedString(lowComparator, low)
+		highPredicate := orderedString(highComparator, high)
+		predicate = func(v string) bool { return lowPredicate(v) && highPredicate(v) }
+	case condition.ComparatorContains, condition.ComparatorStartsWith, condition.ComparatorEndsWith:
+		value, ok := item.Value.(string)
+		if !ok {
+			return nil
+		}
+		switch item.Comparator {
+		case condition.ComparatorContains:
+			predicate = func(v string) bool { return strings.Contains(v, value) }
+		case condition.ComparatorStartsWith:
+			predicate = func(v string) bool { return strings.HasPrefix(v, value) }
+		case condition.ComparatorEndsWith:
+			predicate = func(v string) bool { return strings.HasSuffix(v, value) }
+		}
+	default:
+		value, ok := item.Value.(string)
+		if !ok {
+			return nil
+		}
+		predicate = orderedString(item.Comparator, value)
+	}
+	if predicate == nil {
+		return stringCompareKernel(values, condVal)
+	}
+	validity := values.Validity()
+	return func(ixs, unknown bitmap, start, n int) error {
+		for i, v := range values.Values()[start : start+n] {
+			if predicate(v) {
+				ixs.set(i)
+			}
+		}
+		validityKernel(validity, ixs, unknown, start)
+		return nil
+	}
+}
+
+// stringCompareKernel scans the strings with CondValue.CompareString, for
+// the case-folding and regex comparators.
+func stringCompareKernel(values sstring.ElementsString, condVal *condition.CondValue) kernel {
+	validity := values.Validity()
+	return func(ixs, unknown bitmap, start, n int) error {
+		for i, v := range values.Values()[start : start+n] {
+			if !validity.IsValid(start + i) {
+				continue
+			}
+			result, err := condVal.CompareString(v)
+			if err != nil {
+				return err
+			}
+			if result {
+				ixs.set(i)
+			}
+		}
+		validityKernel(validity, ixs, unknown, start)
+		return nil
+	}
+}
@@ -0,0 +1,183 @@
+package elements_composite
+
+import (
+	"github.com/hunknownz/godas/condition"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func newTestArray(t testing.TB, values interface{}) *Array {
+	array, err := NewArray(values, "Value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return array
+}
+
+func TestKernelsMatchElementPath(t *testing.T) {
+	arrays := []*Array{
+		newTestArray(t, []int{-3, 0, 2, 5, 7, 11}),
+		newTestArray(t, []float64{-3, 0.5, 2, math.NaN(), 7, 11}),
+		newTestArray(t, []string{"apple", "Banana", "cherry", "NaN", "apricot", "date"}),
+		newTestArray(t, []bool{true, false, true}),
+	}
+	intNull, _ := arrays[0].Elements.Append(true, nil, int64(math.MinInt64))
+	stringNull, _ := arrays[2].Elements.Append(true, nil)
+	floatNull, _ := arrays[1].Elements.Append(true, nil)
+	arrays = append(arrays,
+		&Array{FieldName: "Value", Elements: intNull},
+		&Array{FieldName: "Value", Elements: stringNull},
+		&Array{FieldName: "Value", Elements: floatNull},
+	)
+
+	conds := []*condition.Condition{}
+	add := func(build func(cond *condition.Condition)) {
+		cond := condition.NewCondition(condition.ConditionTypeSeries)
+		build(cond)
+		conds = append(conds, cond)
+	}
+	for _, value := range []interface{}{2, 2.0, 2.5, math.NaN(), "b", true} {
+		for _, comparator := range []string{">", ">=", "=", "!=", "<", "<="} {
+			value, comparator := value, comparator
+			add(func(cond *condition.Condition) { cond.And(comparator, value) })
+		}
+	}
+	add(func(cond *condition.Condition) { cond.And("between", condition.Between(0, 7)) })
+	add(func(cond *condition.Condition) { cond.And("between", condition.Range{Low: 0.5, High: 7}) })
+	add(func(cond *condition.Condition) { cond.And("between", condition.Between("b", "d")) })
+	add(func(cond *condition.Condition) { cond.And("in", []interface{}{2, 7.0, 0.5, "date", true}) })
+	add(func(cond *condition.Condition) { cond.And("not in", []interface{}{2, 11, "apple"}) })
+	add(func(cond *condition.Condition) { cond.And("is_nan", nil).Or("not_nan", nil).AndNot("=", 5) })
+	add(func(cond *condition.Condition) { cond.And("contains", "an").Or("startswith", "ap") })
+	add(func(cond *condition.Condition) { cond.And("endswith", "e").AndNot("iregex", "^b") })
+	add(func(cond *condition.Condition) { cond.And("icontains", "AN") })
+	add(func(cond *condition.Condition) {
+		inner := condition.NewCondition(condition.ConditionTypeSeries)
+		inner.And(">", 0).And("<", 10)
+		cond.AndNotCond(inner).Or("=", 11)
+	})
+	for _, missing := range []int{condition.MissingFalse, condition.MissingTrue} {
+		missing := missing
+		add(func(cond *condition.Condition) { cond.And(">", 1).OrNot("=", "b").SetMissing(missing) })
+		add(func(cond *condition.Condition) { cond.AndNot("between", condition.Between(0, 7)).SetMissing(missing) })
+	}
+	add(func(cond *condition.Condition) {
+		cond.AndFunc("", func(element condition.ElementValue) bool { return !element.NaN() })
+	})
+
+	for _, array := range arrays {
+		for _, cond := range conds {
+			want, wantErr := array.isConditionByElement(cond)
+			got, err := array.IsCondition(cond)
+			if (err != nil) != (wantErr != nil) {
+				t.Errorf("%s %s: got error %v, want %v", array.Type(), cond, err, wantErr)
+				continue
+			}
+			if err == nil && !reflect.DeepEqual(got, want) {
+				t.Errorf("%s %s: got %v, want %v", array.Type(), cond, got, want)
+			}
+		}
+	}
+}
+
+func TestKernelsParallelWords(t *testing.T) {
+	const n = 64*5 + 17
+	values := make([]interface{}, n)
+	for i := range values {
+		if i%7 != 3 {
+			values[i] = int64(i % 10)
+		}
+	}
+	empty := newTestArray(t, []int{})
+	elements, err := empty.Elements.Append(true, values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	array := &Array{FieldName: "Value", Elements: elements}
+
+	cond := condition.NewCondition(condition.ConditionTypeSeries)
+	cond.And(">", 2).AndNot("=", 5).Or("in", []int{0, 1})
+	missing := condition.NewCondition(condition.ConditionTypeSeries)
+	missing.AndNot("<", 4).SetMissing(condition.MissingTrue)
+	for _, cond := range []*condition.Condition{cond, missing} {
+		want, err := array.isConditionByElement(cond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, workers := range []int{1, 2, 3, 8} {
+			got, unknown, err := array.IsConditionKleene(cond, workers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s with %d workers: got %v, want %v", cond, workers, got, want)
+			}
+			for i := range unknown {
+				wantUnknown := i%7 == 3 && cond.Missing() == condition.MissingUnknown
+				if unknown[i] != wantUnknown {
+					t.Errorf("%s with %d workers: row %d unknown %v", cond, workers, i, unknown[i])
+					break
+				}
+			}
+		}
+	}
+}
+
+func benchmarkArrays(b *testing.B) (ints, strs *Array) {
+	const n = 1000000
+	intValues := make([]int64, n)
+	stringValues := make([]string, n)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < n; i++ {
+		intValues[i] = r.Int63n(1000)
+		stringValues[i] = "id-" + strconv.Itoa(int(intValues[i]))
+	}
+	return newTestArray(b, intValues), newTestArray(b, stringValues)
+}
+
+func benchmarkCondition() (intCond, stringCond *condition.Condition) {
+	intCond = condition.NewCondition(condition.ConditionTypeSeries)
+	intCond.And(">=", 100).And("<", 900).OrNot("in", []int{1, 2, 3})
+	stringCond = condition.NewCondition(condition.ConditionTypeSeries)
+	stringCond.And("startswith", "id-1").Or("=", "id-500")
+	return
+}
+
+func BenchmarkIsConditionInt(b *testing.B) {
+	ints, _ := benchmarkArrays(b)
+	cond, _ := benchmarkCondition()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ints.IsCondition(cond)
+	}
+}
+
+func BenchmarkIsConditionIntByElement(b *testing.B) {
+	ints, _ := benchmarkArrays(b)
+	cond, _ := benchmarkCondition()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ints.isConditionByElement(cond)
+	}
+}
+
+func BenchmarkIsConditionString(b *testing.B) {
+	_, strs := benchmarkArrays(b)
+	_, cond := benchmarkCondition()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		strs.IsCondition(cond)
+	}
+}
+
+func BenchmarkIsConditionStringByElement(b *testing.B) {
+	_, strs := benchmarkArrays(b)
+	_, cond := benchmarkCondition()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		strs.isConditionByElement(cond)
+	}
+}
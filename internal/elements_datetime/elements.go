@@ -0,0 +1,36 @@
+package elements_datetime
+
+import (
+	"github.com/hunknownz/godas/internal/elements"
+	"time"
+)
+
+// NewElementsDatetime returns elements of the datetimes values, in
+// nanoseconds since the Unix epoch, displayed in location, UTC when nil.
+// They are null where isNull is set, whatever their value.
+func NewElementsDatetime(values []int64, isNull []bool, location *time.Location) (newElements ElementsDatetime) {
+	if location == nil {
+		location = time.UTC
+	}
+	newElements = ElementsDatetime{
+		values:   values,
+		validity: elements.NewValidity(isNull),
+		location: location,
+	}
+	return
+}
+
+// NewElementsTime returns elements of the times, displayed in the location
+// of the first one.
+func NewElementsTime(times []time.Time) (newElements ElementsDatetime) {
+	values := make([]int64, len(times))
+	for i, t := range times {
+		values[i] = t.UnixNano()
+	}
+	var location *time.Location
+	if len(times) > 0 {
+		location = times[0].Location()
+	}
+	newElements = NewElementsDatetime(values, nil, location)
+	return
+}
@@ -0,0 +1,154 @@
+package elements_datetime
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/index"
+	"github.com/hunknownz/godas/internal/elements"
+	"github.com/hunknownz/godas/types"
+	"reflect"
+	"time"
+)
+
+type ElementDatetime = int64
+
+// ElementsDatetime holds datetimes as int64 nanoseconds since the Unix
+// epoch and their validity, the location only affecting how they are
+// displayed. Datetimes must lie between the years 1678 and 2262.
+type ElementsDatetime struct {
+	values   []ElementDatetime
+	validity elements.Validity
+	location *time.Location
+}
+
+func (elements ElementsDatetime) Type() (sType types.Type) {
+	return types.TypeDatetime
+}
+
+func (elements ElementsDatetime) Len() (sLen int) {
+	return len(elements.values)
+}
+
+func (elements ElementsDatetime) String() string {
+	return elements.validity.Format(elements.Len(), func(i int) string {
+		return elements.Time(i).Format(time.RFC3339Nano)
+	})
+}
+
+// Values returns the nanoseconds of the elements, which are shared.
+func (elements ElementsDatetime) Values() []ElementDatetime {
+	return elements.values
+}
+
+func (elements ElementsDatetime) Validity() elements.Validity {
+	return elements.validity
+}
+
+// TimeLocation returns the location the datetimes are displayed in.
+func (elements ElementsDatetime) TimeLocation() *time.Location {
+	return elements.location
+}
+
+// Time returns element i as a time in the location of the elements.
+func (elements ElementsDatetime) Time(i int) time.Time {
+	return time.Unix(0, elements.values[i]).In(elements.location)
+}
+
+func (elements ElementsDatetime) Copy() (newElements elements.Elements) {
+	newSlice := make([]ElementDatetime, elements.Len())
+	copy(newSlice, elements.values)
+
+	newElements = ElementsDatetime{
+		values:   newSlice,
+		validity: elements.validity.Copy(),
+		location: elements.location,
+	}
+	return
+}
+
+func (elements ElementsDatetime) Subset(idx index.IndexInt) (newElements elements.Elements, err error) {
+	idxLen := len(idx)
+	if elements.Len() < idxLen {
+		err = errors.New(fmt.Sprintf("index size %d off elements_datetime size %d", idxLen, elements.Len()))
+		return
+	}
+	newSlice := make([]ElementDatetime, idxLen)
+	for newElementsI, indexI := range idx {
+		newSlice[newElementsI] = elements.values[indexI]
+	}
+
+	newElements = ElementsDatetime{
+		values:   newSlice,
+		validity: elements.validity.Subset(idx),
+		location: elements.location,
+	}
+	return
+}
+
+func (elements ElementsDatetime) IsNaN() []bool {
+	return elements.IsNull()
+}
+
+func (elements ElementsDatetime) IsNull() []bool {
+	return elements.validity.IsNull(elements.Len())
+}
+
+func (elements ElementsDatetime) Location(coord int) (element elements.ElementValue, err error) {
+	if coord < 0 {
+		err = errors.New(fmt.Sprintf("invalid index %d (index must be non-negative)", coord))
+		return
+	}
+	datetimeLen := elements.Len()
+	if coord >= datetimeLen {
+		err = errors.New(fmt.Sprintf("invalid index %d (out of bounds for %d-element container)", coord, datetimeLen))
+		return
+	}
+	element.Type = types.TypeDatetime
+	element.IsNull = !elements.validity.IsValid(coord)
+	element.IsNaN = element.IsNull
+	if element.IsNull {
+		element.Value = time.Time{}
+	} else {
+		element.Value = elements.Time(coord)
+	}
+	return
+}
+
+func (elements ElementsDatetime) Swap(i, j int) {
+	elements.values[i], elements.values[j] = elements.values[j], elements.values[i]
+	elements.validity.Swap(i, j)
+}
+
+// Append appends time.Time values, nil appending a null element.
+func (elements ElementsDatetime) Append(copy bool, values ...interface{}) (newElements elements.Elements, err error) {
+	var nElements ElementsDatetime
+	if !copy {
+		nElements = elements
+	} else {
+		nElements = elements.Copy().(ElementsDatetime)
+	}
+
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		if _, ok := value.(time.Time); !ok {
+			err = errors.New(fmt.Sprintf("datetime elements can't append %s", reflect.TypeOf(value).String()))
+			return
+		}
+	}
+
+	isNull := make([]bool, len(values))
+	for i, value := range values {
+		if value == nil {
+			nElements.values = append(nElements.values, 0)
+			isNull[i] = true
+			continue
+		}
+		nElements.values = append(nElements.values, value.(time.Time).UnixNano())
+	}
+	nElements.validity = nElements.validity.Append(elements.Len(), isNull...)
+	newElements = nElements
+
+	return
+}
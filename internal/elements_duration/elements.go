@@ -0,0 +1,15 @@
+package elements_duration
+
+import (
+	"github.com/hunknownz/godas/internal/elements"
+)
+
+// NewElementsDuration returns elements of the durations values, in
+// nanoseconds, which are null where isNull is set, whatever their value.
+func NewElementsDuration(values []int64, isNull []bool) (newElements ElementsDuration) {
+	newElements = ElementsDuration{
+		values:   values,
+		validity: elements.NewValidity(isNull),
+	}
+	return
+}
@@ -0,0 +1,135 @@
+package elements_duration
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/index"
+	"github.com/hunknownz/godas/internal/elements"
+	"github.com/hunknownz/godas/types"
+	"reflect"
+	"time"
+)
+
+type ElementDuration = int64
+
+// ElementsDuration holds durations as int64 nanoseconds and their validity.
+type ElementsDuration struct {
+	values   []ElementDuration
+	validity elements.Validity
+}
+
+func (elements ElementsDuration) Type() (sType types.Type) {
+	return types.TypeDuration
+}
+
+func (elements ElementsDuration) Len() (sLen int) {
+	return len(elements.values)
+}
+
+func (elements ElementsDuration) String() string {
+	return elements.validity.Format(elements.Len(), func(i int) string {
+		return time.Duration(elements.values[i]).String()
+	})
+}
+
+// Values returns the nanoseconds of the elements, which are shared.
+func (elements ElementsDuration) Values() []ElementDuration {
+	return elements.values
+}
+
+func (elements ElementsDuration) Validity() elements.Validity {
+	return elements.validity
+}
+
+func (elements ElementsDuration) Copy() (newElements elements.Elements) {
+	newSlice := make([]ElementDuration, elements.Len())
+	copy(newSlice, elements.values)
+
+	newElements = ElementsDuration{
+		values:   newSlice,
+		validity: elements.validity.Copy(),
+	}
+	return
+}
+
+func (elements ElementsDuration) Subset(idx index.IndexInt) (newElements elements.Elements, err error) {
+	idxLen := len(idx)
+	if elements.Len() < idxLen {
+		err = errors.New(fmt.Sprintf("index size %d off elements_duration size %d", idxLen, elements.Len()))
+		return
+	}
+	newSlice := make([]ElementDuration, idxLen)
+	for newElementsI, indexI := range idx {
+		newSlice[newElementsI] = elements.values[indexI]
+	}
+
+	newElements = ElementsDuration{
+		values:   newSlice,
+		validity: elements.validity.Subset(idx),
+	}
+	return
+}
+
+func (elements ElementsDuration) IsNaN() []bool {
+	return elements.IsNull()
+}
+
+func (elements ElementsDuration) IsNull() []bool {
+	return elements.validity.IsNull(elements.Len())
+}
+
+func (elements ElementsDuration) Location(coord int) (element elements.ElementValue, err error) {
+	if coord < 0 {
+		err = errors.New(fmt.Sprintf("invalid index %d (index must be non-negative)", coord))
+		return
+	}
+	durationLen := elements.Len()
+	if coord >= durationLen {
+		err = errors.New(fmt.Sprintf("invalid index %d (out of bounds for %d-element container)", coord, durationLen))
+		return
+	}
+	element.Value = time.Duration(elements.values[coord])
+	element.Type = types.TypeDuration
+	element.IsNull = !elements.validity.IsValid(coord)
+	element.IsNaN = element.IsNull
+	return
+}
+
+func (elements ElementsDuration) Swap(i, j int) {
+	elements.values[i], elements.values[j] = elements.values[j], elements.values[i]
+	elements.validity.Swap(i, j)
+}
+
+// Append appends time.Duration values, nil appending a null element.
+func (elements ElementsDuration) Append(copy bool, values ...interface{}) (newElements elements.Elements, err error) {
+	var nElements ElementsDuration
+	if !copy {
+		nElements = elements
+	} else {
+		nElements = elements.Copy().(ElementsDuration)
+	}
+
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		if _, ok := value.(time.Duration); !ok {
+			err = errors.New(fmt.Sprintf("duration elements can't append %s", reflect.TypeOf(value).String()))
+			return
+		}
+	}
+
+	isNull := make([]bool, len(values))
+	for i, value := range values {
+		if value == nil {
+			nElements.values = append(nElements.values, 0)
+			isNull[i] = true
+			continue
+		}
+		nElements.values = append(nElements.values, int64(value.(time.Duration)))
+	}
+	nElements.validity = nElements.validity.Append(elements.Len(), isNull...)
+	newElements = nElements
+
+	return
+}
@@ -1,7 +1,28 @@
 package elements_float
 
+import (
+	"github.com/hunknownz/godas/internal/elements"
+	"math"
+)
 
-func NewElementsFloat64(elements []float64) (newElements ElementsFloat64) {
-	newElements = elements
+func NewElementsFloat64(values []float64) (newElements ElementsFloat64) {
+	newElements = ElementsFloat64{
+		values: values,
+	}
 	return
-}
\ No newline at end of file
+}
+
+// NewNullableElementsFloat64 returns elements whose elements are null where
+// isNull is set. The values of null elements are set to NaN.
+func NewNullableElementsFloat64(values []float64, isNull []bool) (newElements ElementsFloat64) {
+	for i, null := range isNull {
+		if null {
+			values[i] = math.NaN()
+		}
+	}
+	newElements = ElementsFloat64{
+		values:   values,
+		validity: elements.NewValidity(isNull),
+	}
+	return
+}
@@ -12,25 +12,46 @@ import (
 )
 
 type ElementFloat64 = float64
-type ElementsFloat64 []ElementFloat64
+
+// ElementsFloat64 holds float64 values and their validity. A NaN value is
+// a missing but valid element, distinct from a null one. Null elements have
+// the value NaN, so arithmetic on the values propagates them.
+type ElementsFloat64 struct {
+	values   []ElementFloat64
+	validity elements.Validity
+}
 
 func (elements ElementsFloat64) Type() (sType types.Type) {
 	return types.TypeFloat
 }
 
 func (elements ElementsFloat64) Len() (sLen int) {
-	return len(elements)
+	return len(elements.values)
 }
 
 func (elements ElementsFloat64) String() string {
-	return fmt.Sprint(elements)
+	return elements.validity.Format(elements.Len(), func(i int) string {
+		return fmt.Sprint(elements.values[i])
+	})
+}
+
+// Values returns the values of the elements, which are shared.
+func (elements ElementsFloat64) Values() []ElementFloat64 {
+	return elements.values
+}
+
+func (elements ElementsFloat64) Validity() elements.Validity {
+	return elements.validity
 }
 
 func (elements ElementsFloat64) Copy() (newElements elements.Elements) {
 	newSlice := make([]ElementFloat64, elements.Len())
-	copy(newSlice, elements)
+	copy(newSlice, elements.values)
 
-	newElements = ElementsFloat64(newSlice)
+	newElements = ElementsFloat64{
+		values:   newSlice,
+		validity: elements.validity.Copy(),
+	}
 	return
 }
 
@@ -42,23 +63,31 @@ func (elements ElementsFloat64) Subset(idx index.IndexInt) (newElements elements
 	}
 	newSlice := make([]ElementFloat64, idxLen)
 	for newElementsI, indexI := range idx {
-		newSlice[newElementsI] = elements[indexI]
+		newSlice[newElementsI] = elements.values[indexI]
 	}
 
-	newElements = ElementsFloat64(newSlice)
+	newElements = ElementsFloat64{
+		values:   newSlice,
+		validity: elements.validity.Subset(idx),
+	}
 	return
 }
 
+// IsNaN reports the null and NaN elements.
 func (elements ElementsFloat64) IsNaN() []bool {
 	elementsLen := elements.Len()
 	nanElements := make([]bool, elementsLen)
 	for i := 0; i < elementsLen; i++ {
-		isNaN := math.IsNaN(elements[i])
+		isNaN := math.IsNaN(elements.values[i])
 		nanElements[i] = isNaN
 	}
 	return nanElements
 }
 
+func (elements ElementsFloat64) IsNull() []bool {
+	return elements.validity.IsNull(elements.Len())
+}
+
 func (elements ElementsFloat64) Location(coord int) (element elements.ElementValue, err error) {
 	if coord < 0 {
 		err = errors.New(fmt.Sprintf("invalid index %d (index must be non-negative)", coord))
@@ -69,15 +98,19 @@ func (elements ElementsFloat64) Location(coord int) (element elements.ElementVal
 		err = errors.New(fmt.Sprintf("invalid index %d (out of bounds for %d-element container)", coord, float64Len))
 		return
 	}
-	element.Value = elements[coord]
+	element.Value = elements.values[coord]
 	element.Type = types.TypeFloat
+	element.IsNull = !elements.validity.IsValid(coord)
+	element.IsNaN = math.IsNaN(elements.values[coord])
 	return
 }
 
 func (elements ElementsFloat64) Swap(i, j int) {
-	elements[i], elements[j] = elements[j], elements[i]
+	elements.values[i], elements.values[j] = elements.values[j], elements.values[i]
+	elements.validity.Swap(i, j)
 }
 
+// Append appends float values, nil appending a null element.
 func (elements ElementsFloat64) Append(copy bool, values ...interface{}) (newElements elements.Elements, err error) {
 	var nElements ElementsFloat64
 	if !copy {
@@ -87,6 +120,9 @@ func (elements ElementsFloat64) Append(copy bool, values ...interface{}) (newEle
 	}
 
 	for _, value := range values {
+		if value == nil {
+			continue
+		}
 		kind := reflect.TypeOf(value).Kind()
 		if kind != reflect.Float32 && kind != reflect.Float64 {
 			err = errors.New(fmt.Sprintf("float elements can't append %s", kind.String()))
@@ -94,17 +130,22 @@ func (elements ElementsFloat64) Append(copy bool, values ...interface{}) (newEle
 		}
 	}
 
-	for _, value := range values {
+	isNull := make([]bool, len(values))
+	for i, value := range values {
 		switch value.(type) {
+		case nil:
+			nElements.values = append(nElements.values, math.NaN())
+			isNull[i] = true
 		case float32:
 			val := value.(float32)
-			nElements = append(nElements, float64(val))
+			nElements.values = append(nElements.values, float64(val))
 		case float64:
 			val := value.(float64)
-			nElements = append(nElements, val)
+			nElements.values = append(nElements.values, val)
 		}
 	}
+	nElements.validity = nElements.validity.Append(elements.Len(), isNull...)
 	newElements = nElements
 
 	return
-}
\ No newline at end of file
+}
@@ -1,6 +1,22 @@
 package elements_int
 
-func NewElementsInt64(elements []int64) (newElements ElementsInt64) {
-	newElements = elements
+import (
+	"github.com/hunknownz/godas/internal/elements"
+)
+
+func NewElementsInt64(values []int64) (newElements ElementsInt64) {
+	newElements = ElementsInt64{
+		values: values,
+	}
+	return
+}
+
+// NewNullableElementsInt64 returns elements whose elements are null where
+// isNull is set, whatever their value.
+func NewNullableElementsInt64(values []int64, isNull []bool) (newElements ElementsInt64) {
+	newElements = ElementsInt64{
+		values:   values,
+		validity: elements.NewValidity(isNull),
+	}
 	return
-}
\ No newline at end of file
+}
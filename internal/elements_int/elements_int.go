@@ -6,34 +6,50 @@ import (
 	"github.com/hunknownz/godas/index"
 	"github.com/hunknownz/godas/internal/elements"
 	"github.com/hunknownz/godas/types"
-	"math"
 	"reflect"
+	"strconv"
 )
 
 type ElementInt64 = int64
-type ElementsInt64 []ElementInt64
 
-const (
-	ElementNaNInt64 ElementInt64 = math.MinInt64
-)
+// ElementsInt64 holds int64 values and their validity. Null elements have
+// the value 0, so every int64 is a legitimate value.
+type ElementsInt64 struct {
+	values   []ElementInt64
+	validity elements.Validity
+}
 
 func (elements ElementsInt64) Type() (sType types.Type) {
 	return types.TypeInt
 }
 
 func (elements ElementsInt64) Len() (sLen int) {
-	return len(elements)
+	return len(elements.values)
 }
 
 func (elements ElementsInt64) String() string {
-	return fmt.Sprint(elements)
+	return elements.validity.Format(elements.Len(), func(i int) string {
+		return strconv.FormatInt(elements.values[i], 10)
+	})
+}
+
+// Values returns the values of the elements, which are shared.
+func (elements ElementsInt64) Values() []ElementInt64 {
+	return elements.values
+}
+
+func (elements ElementsInt64) Validity() elements.Validity {
+	return elements.validity
 }
 
 func (elements ElementsInt64) Copy() (newElements elements.Elements) {
 	newSlice := make([]ElementInt64, elements.Len())
-	copy(newSlice, elements)
+	copy(newSlice, elements.values)
 
-	newElements = ElementsInt64(newSlice)
+	newElements = ElementsInt64{
+		values:   newSlice,
+		validity: elements.validity.Copy(),
+	}
 	return
 }
 
@@ -45,21 +61,22 @@ func (elements ElementsInt64) Subset(idx index.IndexInt) (newElements elements.E
 	}
 	newSlice := make([]ElementInt64, idxLen)
 	for newElementsI, indexI := range idx {
-		newSlice[newElementsI] = elements[indexI]
+		newSlice[newElementsI] = elements.values[indexI]
 	}
 
-	newElements = ElementsInt64(newSlice)
+	newElements = ElementsInt64{
+		values:   newSlice,
+		validity: elements.validity.Subset(idx),
+	}
 	return
 }
 
 func (elements ElementsInt64) IsNaN() []bool {
-	elementsLen := elements.Len()
-	nanElements := make([]bool, elementsLen)
-	for i := 0; i < elementsLen; i++ {
-		isNaN := elements[i] == ElementNaNInt64
-		nanElements[i] = isNaN
-	}
-	return nanElements
+	return elements.IsNull()
+}
+
+func (elements ElementsInt64) IsNull() []bool {
+	return elements.validity.IsNull(elements.Len())
 }
 
 func (elements ElementsInt64) Location(coord int) (element elements.ElementValue, err error) {
@@ -72,15 +89,19 @@ func (elements ElementsInt64) Location(coord int) (element elements.ElementValue
 		err = errors.New(fmt.Sprintf("invalid index %d (out of bounds for %d-element container)", coord, int64Len))
 		return
 	}
-	element.Value = elements[coord]
+	element.Value = elements.values[coord]
 	element.Type = types.TypeInt
+	element.IsNull = !elements.validity.IsValid(coord)
+	element.IsNaN = element.IsNull
 	return
 }
 
 func (elements ElementsInt64) Swap(i, j int) {
-	elements[i], elements[j] = elements[j], elements[i]
+	elements.values[i], elements.values[j] = elements.values[j], elements.values[i]
+	elements.validity.Swap(i, j)
 }
 
+// Append appends int values, nil appending a null element.
 func (elements ElementsInt64) Append(copy bool, values ...interface{}) (newElements elements.Elements, err error) {
 	var nElements ElementsInt64
 	if !copy {
@@ -90,34 +111,42 @@ func (elements ElementsInt64) Append(copy bool, values ...interface{}) (newEleme
 	}
 
 	for _, value := range values {
+		if value == nil {
+			continue
+		}
 		kind := reflect.TypeOf(value).Kind()
 		if kind != reflect.Int && kind != reflect.Int8 && kind != reflect.Int16 &&
 			kind != reflect.Int32 && kind != reflect.Int64 {
 			err = errors.New(fmt.Sprintf("int elements can't append %s", kind.String()))
 			return
-			}
+		}
 	}
 
-	for _, value := range values {
+	isNull := make([]bool, len(values))
+	for i, value := range values {
 		switch value.(type) {
+		case nil:
+			nElements.values = append(nElements.values, 0)
+			isNull[i] = true
 		case int:
 			val := value.(int)
-			nElements = append(nElements, int64(val))
+			nElements.values = append(nElements.values, int64(val))
 		case int8:
 			val := value.(int8)
-			nElements = append(nElements, int64(val))
+			nElements.values = append(nElements.values, int64(val))
 		case int16:
 			val := value.(int16)
-			nElements = append(nElements, int64(val))
+			nElements.values = append(nElements.values, int64(val))
 		case int32:
 			val := value.(int32)
-			nElements = append(nElements, int64(val))
+			nElements.values = append(nElements.values, int64(val))
 		case int64:
 			val := value.(int64)
-			nElements = append(nElements, val)
+			nElements.values = append(nElements.values, val)
 		}
 	}
+	nElements.validity = nElements.validity.Append(elements.Len(), isNull...)
 	newElements = nElements
 
 	return
-}
\ No newline at end of file
+}
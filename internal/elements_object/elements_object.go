@@ -6,17 +6,18 @@ import (
 	"github.com/hunknownz/godas/index"
 	"github.com/hunknownz/godas/internal/elements"
 	"github.com/hunknownz/godas/types"
-	"reflect"
 )
 
 type ElementObject = interface{}
+
+// ElementsObject holds objects, nil being a null object.
 type ElementsObject struct {
 	itemsLen int
 	items []ElementObject
 }
 
 func (elements ElementsObject) Type() (sType types.Type) {
-	return types.TypeInt
+	return types.TypeObject
 }
 
 func (elements ElementsObject) Len() (sLen int) {
@@ -80,6 +81,10 @@ func (elements ElementsObject) IsNaN() []bool {
 	return nanElements
 }
 
+func (elements ElementsObject) IsNull() []bool {
+	return elements.IsNaN()
+}
+
 func (elements ElementsObject) Location(coord int) (element elements.ElementValue, err error) {
 	if coord < 0 {
 		err = errors.New(fmt.Sprintf("invalid index %d (index must be non-negative)", coord))
@@ -96,6 +101,8 @@ func (elements ElementsObject) Location(coord int) (element elements.ElementValu
 		element.Value = elements.items[coord]
 	}
 	element.Type = types.TypeObject
+	element.IsNull = element.Value == nil
+	element.IsNaN = element.IsNull
 	return
 }
 
@@ -103,6 +110,7 @@ func (elements ElementsObject) Swap(i, j int) {
 	elements.items[i], elements.items[j] = elements.items[j], elements.items[i]
 }
 
+// Append appends values of any type, nil appending a null element.
 func (elements ElementsObject) Append(copy bool, values ...interface{}) (newElements elements.Elements, err error) {
 	var nElements ElementsObject
 	if !copy {
@@ -111,14 +119,6 @@ func (elements ElementsObject) Append(copy bool, values ...interface{}) (newElem
 		nElements = elements.Copy().(ElementsObject)
 	}
 
-	for _, value := range values {
-		kind := reflect.TypeOf(value).Kind()
-		if kind != reflect.Interface {
-			err = errors.New(fmt.Sprintf("object elements can't append %s", kind.String()))
-			return
-		}
-	}
-
 	nElements.items = append(nElements.items, values...)
 	nElements.itemsLen = nElements.itemsLen + len(values)
 	newElements = nElements
@@ -1,6 +1,22 @@
 package elements_string
 
-func NewElementsString(elements []string) (newElements ElementsString) {
-	newElements = elements
+import (
+	"github.com/hunknownz/godas/internal/elements"
+)
+
+func NewElementsString(values []string) (newElements ElementsString) {
+	newElements = ElementsString{
+		values: values,
+	}
+	return
+}
+
+// NewNullableElementsString returns elements whose elements are null where
+// isNull is set, whatever their value.
+func NewNullableElementsString(values []string, isNull []bool) (newElements ElementsString) {
+	newElements = ElementsString{
+		values:   values,
+		validity: elements.NewValidity(isNull),
+	}
 	return
-}
\ No newline at end of file
+}
@@ -10,29 +10,45 @@ import (
 )
 
 type ElementString = string
-type ElementsString []ElementString
 
-const (
-	ElementNaNString ElementString = "NaN"
-)
+// ElementsString holds string values and their validity. Null elements have
+// the value "", so every string, "NaN" included, is a legitimate value.
+type ElementsString struct {
+	values   []ElementString
+	validity elements.Validity
+}
 
 func (elements ElementsString) Type() (sType types.Type) {
 	return types.TypeString
 }
 
 func (elements ElementsString) Len() (sLen int) {
-	return len(elements)
+	return len(elements.values)
 }
 
 func (elements ElementsString) String() string {
-	return fmt.Sprint(elements)
+	return elements.validity.Format(elements.Len(), func(i int) string {
+		return elements.values[i]
+	})
+}
+
+// Values returns the values of the elements, which are shared.
+func (elements ElementsString) Values() []ElementString {
+	return elements.values
+}
+
+func (elements ElementsString) Validity() elements.Validity {
+	return elements.validity
 }
 
 func (elements ElementsString) Copy() (newElements elements.Elements) {
 	newSlice := make([]ElementString, elements.Len())
-	copy(newSlice, elements)
+	copy(newSlice, elements.values)
 
-	newElements = ElementsString(newSlice)
+	newElements = ElementsString{
+		values:   newSlice,
+		validity: elements.validity.Copy(),
+	}
 	return
 }
 
@@ -44,21 +60,22 @@ func (elements ElementsString) Subset(idx index.IndexInt) (newElements elements.
 	}
 	newSlice := make([]ElementString, idxLen)
 	for newElementsI, indexI := range idx {
-		newSlice[newElementsI] = elements[indexI]
+		newSlice[newElementsI] = elements.values[indexI]
 	}
 
-	newElements = ElementsString(newSlice)
+	newElements = ElementsString{
+		values:   newSlice,
+		validity: elements.validity.Subset(idx),
+	}
 	return
 }
 
 func (elements ElementsString) IsNaN() []bool {
-	elementsLen := elements.Len()
-	nanElements := make([]bool, elementsLen)
-	for i := 0; i < elementsLen; i++ {
-		isNaN := elements[i] == ElementNaNString
-		nanElements[i] = isNaN
-	}
-	return nanElements
+	return elements.IsNull()
+}
+
+func (elements ElementsString) IsNull() []bool {
+	return elements.validity.IsNull(elements.Len())
 }
 
 func (elements ElementsString) Location(coord int) (element elements.ElementValue, err error) {
@@ -71,15 +88,19 @@ func (elements ElementsString) Location(coord int) (element elements.ElementValu
 		err = errors.New(fmt.Sprintf("invalid index %d (out of bounds for %d-element container)", coord, stringLen))
 		return
 	}
-	element.Value = elements[coord]
+	element.Value = elements.values[coord]
 	element.Type = types.TypeString
+	element.IsNull = !elements.validity.IsValid(coord)
+	element.IsNaN = element.IsNull
 	return
 }
 
 func (elements ElementsString) Swap(i, j int) {
-	elements[i], elements[j] = elements[j], elements[i]
+	elements.values[i], elements.values[j] = elements.values[j], elements.values[i]
+	elements.validity.Swap(i, j)
 }
 
+// Append appends string values, nil appending a null element.
 func (elements ElementsString) Append(copy bool, values ...interface{}) (newElements elements.Elements, err error) {
 	var nElements ElementsString
 	if !copy {
@@ -89,6 +110,9 @@ func (elements ElementsString) Append(copy bool, values ...interface{}) (newElem
 	}
 
 	for _, value := range values {
+		if value == nil {
+			continue
+		}
 		kind := reflect.TypeOf(value).Kind()
 		if kind != reflect.String {
 			err = errors.New(fmt.Sprintf("string elements can't append %s", kind.String()))
@@ -96,11 +120,18 @@ func (elements ElementsString) Append(copy bool, values ...interface{}) (newElem
 		}
 	}
 
-	for _, value := range values {
+	isNull := make([]bool, len(values))
+	for i, value := range values {
+		if value == nil {
+			nElements.values = append(nElements.values, "")
+			isNull[i] = true
+			continue
+		}
 		val := value.(string)
-		nElements = append(nElements, val)
+		nElements.values = append(nElements.values, val)
 	}
+	nElements.validity = nElements.validity.Append(elements.Len(), isNull...)
 	newElements = nElements
 
 	return
-}
\ No newline at end of file
+}
@@ -6,6 +6,8 @@ import (
 	"io"
 )
 
+// NewFromCSV reads the columns of a CSV with a header row as string slices,
+// empty fields being nil.
 func NewFromCSV(reader io.Reader) (dataMap map[string]interface{}, headers []string, err error) {
 	csvReader := csv.NewReader(reader)
 	csvReader.LazyQuotes = true
@@ -19,9 +21,11 @@ func NewFromCSV(reader io.Reader) (dataMap map[string]interface{}, headers []str
 	headers = records[0]
 	rowNum := len(records)
 	for columnI, header := range headers {
-		dataColumn := make([]string, rowNum-1)
+		dataColumn := make([]*string, rowNum-1)
 		for rowI := 1; rowI < rowNum; rowI++ {
-			dataColumn[rowI-1] = records[rowI][columnI]
+			if records[rowI][columnI] != "" {
+				dataColumn[rowI-1] = &records[rowI][columnI]
+			}
 		}
 		dataMap[header] = dataColumn
 	}
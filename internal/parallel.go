@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"sync"
+)
+
+// ParallelRange splits [0, n) into at most workers contiguous ranges and
+// runs f on them concurrently. With a single worker f runs on the calling
+// goroutine. The error of the first failed range is returned, so the
+// result doesn't depend on scheduling.
+func ParallelRange(workers, n int, f func(start, end int) error) (err error) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		if n > 0 {
+			err = f(0, n)
+		}
+		return
+	}
+
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		start, end := w*n/workers, (w+1)*n/workers
+		go func(w, start, end int) {
+			defer wg.Done()
+			errs[w] = f(start, end)
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	for _, e := range errs {
+		if e != nil {
+			err = e
+			return
+		}
+	}
+	return
+}
+
+// ParallelEach runs f for every i in [0, n) on at most workers goroutines.
+// The error of the lowest failed i is returned.
+func ParallelEach(workers, n int, f func(i int) error) (err error) {
+	errs := make([]error, n)
+	ParallelRange(workers, n, func(start, end int) error {
+		for i := start; i < end; i++ {
+			errs[i] = f(i)
+		}
+		return nil
+	})
+	for _, e := range errs {
+		if e != nil {
+			err = e
+			return
+		}
+	}
+	return
+}
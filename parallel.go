@@ -0,0 +1,49 @@
+package godas
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+var parallelism int32 = 1
+
+// SetParallelism sets the default number of goroutines that condition
+// evaluation, Subset, Copy and the column-wise aggregations split their
+// work across. 1, the initial value, runs everything serially, and n < 1
+// uses runtime.GOMAXPROCS(0). Results are identical to the serial ones.
+func SetParallelism(n int) {
+	if n < 1 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	atomic.StoreInt32(&parallelism, int32(n))
+}
+
+// Parallelism returns the default number of goroutines set by
+// SetParallelism.
+func Parallelism() int {
+	return int(atomic.LoadInt32(&parallelism))
+}
+
+// Parallel returns a dataframe sharing the data of df whose operations use
+// n goroutines instead of the default, n < 1 meaning
+// runtime.GOMAXPROCS(0). Dataframes returned by those operations use the
+// default again. Custom predicates must be safe for concurrent use when
+// n > 1.
+func (df *DataFrame) Parallel(n int) *DataFrame {
+	if n < 1 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	return &DataFrame{
+		data:        df.data,
+		sourceType:  df.sourceType,
+		Err:         df.Err,
+		parallelism: n,
+	}
+}
+
+func (df *DataFrame) workers() int {
+	if df.parallelism > 0 {
+		return df.parallelism
+	}
+	return Parallelism()
+}
@@ -0,0 +1,114 @@
+package godas
+
+import (
+	"github.com/hunknownz/godas/condition"
+	"github.com/hunknownz/godas/index"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func frameEqual(a, b *DataFrame) bool {
+	if a.NumRow() != b.NumRow() || !reflect.DeepEqual(a.data.Fields, b.data.Fields) {
+		return false
+	}
+	for _, column := range a.data.Fields {
+		for i := 0; i < a.NumRow(); i++ {
+			x, _ := a.At(i, column)
+			y, _ := b.At(i, column)
+			xv, yv := x.MustInterface(), y.MustInterface()
+			xf, xok := xv.(float64)
+			yf, yok := yv.(float64)
+			if xok && yok && math.IsNaN(xf) && math.IsNaN(yf) {
+				continue
+			}
+			if !reflect.DeepEqual(xv, yv) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestDataFrameParallel(t *testing.T) {
+	type record struct {
+		Name  string
+		Group int
+		Value float64
+		Count int
+	}
+	records := make([]record, 1000)
+	for i := range records {
+		value := float64(i%37) / 3
+		if i%11 == 0 {
+			value = math.NaN()
+		}
+		records[i] = record{"n" + strconv.Itoa(i), i % 7, value, i * 13 % 101}
+	}
+	df, err := NewFromStructs(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := df.Parallel(8)
+
+	cond := NewDataFrameCondition()
+	cond.Or(">", 5.0, "Value").And("<", int64(80), "Count").OrNot("startswith", "n9", "Name")
+	cond.AndFunc("Count", func(v condition.ElementValue) bool {
+		count, _ := v.Int()
+		return count%3 != 0
+	})
+	want, err := df.Filter(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := pdf.Filter(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !frameEqual(got, want) {
+		t.Error("filter: parallel result differs from serial")
+	}
+
+	ixs := index.IndexInt{999, 3, 500, 3, 0}
+	want, _ = df.Subset(ixs)
+	got, _ = pdf.Subset(ixs)
+	if !frameEqual(got, want) {
+		t.Error("subset: parallel result differs from serial")
+	}
+
+	if !frameEqual(pdf.Copy(), df.Copy()) {
+		t.Error("copy: parallel result differs from serial")
+	}
+
+	for name, f := range map[string]func(df *DataFrame) (*DataFrame, error){
+		"describe": (*DataFrame).Describe,
+		"cov":      (*DataFrame).Cov,
+		"corr": func(df *DataFrame) (*DataFrame, error) {
+			return df.Corr(CorrSpearman)
+		},
+		"cumsum": func(df *DataFrame) (*DataFrame, error) {
+			return df.CumSum("Group")
+		},
+	} {
+		want, err := f(df)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := f(pdf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !frameEqual(got, want) {
+			t.Errorf("%s: parallel result differs from serial", name)
+		}
+	}
+
+	SetParallelism(4)
+	defer SetParallelism(1)
+	got, _ = df.Filter(cond)
+	want, _ = df.Parallel(1).Filter(cond)
+	if !frameEqual(got, want) {
+		t.Error("filter: global parallelism result differs from serial")
+	}
+}
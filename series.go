@@ -7,13 +7,16 @@ import (
 	"github.com/hunknownz/godas/index"
 	"github.com/hunknownz/godas/internal/elements"
 	sbool "github.com/hunknownz/godas/internal/elements_bool"
+	sdatetime "github.com/hunknownz/godas/internal/elements_datetime"
+	sduration "github.com/hunknownz/godas/internal/elements_duration"
 	"github.com/hunknownz/godas/internal/elements_composite"
 	sfloat "github.com/hunknownz/godas/internal/elements_float"
 	sint "github.com/hunknownz/godas/internal/elements_int"
-	sobject "github.com/hunknownz/godas/internal/elements_object"
 	sstring "github.com/hunknownz/godas/internal/elements_string"
 	"github.com/hunknownz/godas/types"
+	"math"
 	"reflect"
+	"time"
 )
 
 type Series struct {
@@ -74,34 +77,74 @@ func NewSeriesCondition() *condition.Condition {
 	return condition.NewCondition(condition.ConditionTypeSeries)
 }
 
+// intValues returns the values and validity of an int series, or the
+// nanoseconds of a datetime or duration series.
+func (se *Series) intValues() (values []int64, validity elements.Validity) {
+	switch typed := se.array.Elements.(type) {
+	case sint.ElementsInt64:
+		values, validity = typed.Values(), typed.Validity()
+	case sdatetime.ElementsDatetime:
+		values, validity = typed.Values(), typed.Validity()
+	case sduration.ElementsDuration:
+		values, validity = typed.Values(), typed.Validity()
+	}
+	return
+}
+
+// NewIntLessFunc returns a LessFunc ordering an int series, or a datetime
+// or duration series by its nanoseconds, with f.
 func (se *Series) NewIntLessFunc(f IntLessFunc) LessFunc {
-	elements := se.array.Elements.(sint.ElementsInt64)
+	elements, _ := se.intValues()
 	return func(i, j int) bool {
 		return f(elements[i], elements[j])
 	}
 }
 
+// defaultIntLessFunc orders nulls first.
 func (se *Series) defaultIntLessFunc() LessFunc {
-	elements := se.array.Elements.(sint.ElementsInt64)
+	values, validity := se.intValues()
 	return func(i, j int) bool {
-		return elements[i] < elements[j]
+		if !validity.IsValid(i) || !validity.IsValid(j) {
+			return !validity.IsValid(i) && validity.IsValid(j)
+		}
+		return values[i] < values[j]
 	}
 }
 
 func (se *Series) NewFloatLessFunc(f FloatLessFunc) LessFunc {
-	elements := se.array.Elements.(sfloat.ElementsFloat64)
+	elements := se.array.Elements.(sfloat.ElementsFloat64).Values()
 	return func(i, j int) bool {
 		return f(elements[i], elements[j])
 	}
 }
 
+// defaultFloatLessFunc orders nulls and NaNs first.
+func (se *Series) defaultFloatLessFunc() LessFunc {
+	values := se.array.Elements.(sfloat.ElementsFloat64).Values()
+	return func(i, j int) bool {
+		return values[i] < values[j] || (math.IsNaN(values[i]) && !math.IsNaN(values[j]))
+	}
+}
+
 func (se *Series) NewStringLessFunc(f StringLessFunc) LessFunc {
-	elements := se.array.Elements.(sstring.ElementsString)
+	elements := se.array.Elements.(sstring.ElementsString).Values()
 	return func(i, j int) bool {
 		return f(elements[i], elements[j])
 	}
 }
 
+// defaultStringLessFunc orders nulls first.
+func (se *Series) defaultStringLessFunc() LessFunc {
+	elements := se.array.Elements.(sstring.ElementsString)
+	values, validity := elements.Values(), elements.Validity()
+	return func(i, j int) bool {
+		if !validity.IsValid(i) || !validity.IsValid(j) {
+			return !validity.IsValid(i) && validity.IsValid(j)
+		}
+		return values[i] < values[j]
+	}
+}
+
 func (se *Series) NewBoolLessFunc(f BoolLessFunc) LessFunc {
 	elements := se.array.Elements.(sbool.ElementsBool)
 	return func(i, j int) bool {
@@ -135,8 +178,12 @@ func (se *Series) Sort(inPlace bool, ascending bool, orderBy ...LessFunc) (newSe
 	} else {
 		typ := newSe.Type()
 		switch typ {
-		case types.TypeInt:
+		case types.TypeInt, types.TypeDatetime, types.TypeDuration:
 			f = newSe.defaultIntLessFunc()
+		case types.TypeFloat:
+			f = newSe.defaultFloatLessFunc()
+		case types.TypeString:
+			f = newSe.defaultStringLessFunc()
 		case types.TypeBool:
 			f = newSe.defaultBoolLessFunc()
 		}
@@ -151,6 +198,7 @@ func (se *Series) Swap(i, j int) {
 	se.array.Swap(i, j)
 }
 
+// Append appends records to the series, nil records appending nulls.
 func (se *Series) Append(copy bool, records ...interface{}) (newSe *Series, err error) {
 	if !copy {
 		newSe = se
@@ -160,6 +208,23 @@ func (se *Series) Append(copy bool, records ...interface{}) (newSe *Series, err
 
 	seType := se.Type()
 	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		if _, ok := record.(time.Time); ok {
+			if seType != types.TypeDatetime {
+				err = errors.New(fmt.Sprintf("%s series can't append time value", seType))
+				return
+			}
+			continue
+		}
+		if _, ok := record.(time.Duration); ok {
+			if seType != types.TypeDuration {
+				err = errors.New(fmt.Sprintf("%s series can't append duration value", seType))
+				return
+			}
+			continue
+		}
 		kind := reflect.TypeOf(record).Kind()
 		switch kind {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -196,56 +261,21 @@ func (se *Series) Append(copy bool, records ...interface{}) (newSe *Series, err
 	return
 }
 
+// NewSeries returns a series of values, a slice of ints, floats, strings,
+// bools, times, durations or objects. Slices of pointers to ints, floats,
+// strings, bools, times or durations hold nulls as nil pointers.
 func NewSeries(values interface{}, fieldName string) (se *Series, err error) {
-	array := new(elements_composite.Array)
-	switch values.(type) {
-	case []int:
-		vals := values.([]int)
-		valsLen := len(vals)
-		vals64 := make([]int64, valsLen)
-		for i := 0; i < valsLen; i++ {
-			vals64[i] = int64(vals[i])
-		}
-		newElements := sint.NewElementsInt64(vals64)
-		array.Elements = newElements
-	case []int64:
-		vals := values.([]int64)
-		newElements := sint.NewElementsInt64(vals)
-		array.Elements = newElements
-	case []bool:
-		vals := values.([]bool)
-		newElements := sbool.NewElementsBool(vals)
-		array.Elements = newElements
-	case []string:
-		vals := values.([]string)
-		newElements := sstring.NewElementsString(vals)
-		array.Elements = newElements
-	case []float32:
-		vals := values.([]float32)
-		valsLen := len(vals)
-		vals64 := make([]float64, valsLen)
-		for i := 0; i < valsLen; i++ {
-			vals64[i] = float64(vals[i])
-		}
-		newElements := sfloat.NewElementsFloat64(vals64)
-		array.Elements = newElements
-	case []float64:
-		vals := values.([]float64)
-		newElements := sfloat.NewElementsFloat64(vals)
-		array.Elements = newElements
-	case []interface{}:
-		vals := values.([]interface{})
-		newElements := sobject.NewElementsObject(vals)
-		array.Elements = newElements
-	default:
-		typ := reflect.TypeOf(values).Kind().String()
-		err = errors.New(fmt.Sprintf("new series errors: type %s is not supported", typ))
+	array, err := elements_composite.NewArray(values, fieldName)
+	if err != nil {
 		return
 	}
-	array.FieldName = fieldName
 
 	se = &Series{
 		array: array,
 	}
 	return
-}
\ No newline at end of file
+}
+
+func (se *Series) IsNull() []bool {
+	return se.array.IsNull()
+}
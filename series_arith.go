@@ -0,0 +1,509 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/index"
+	sdatetime "github.com/hunknownz/godas/internal/elements_datetime"
+	sduration "github.com/hunknownz/godas/internal/elements_duration"
+	sfloat "github.com/hunknownz/godas/internal/elements_float"
+	sint "github.com/hunknownz/godas/internal/elements_int"
+	sstring "github.com/hunknownz/godas/internal/elements_string"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	arithAdd = "+"
+	arithSub = "-"
+	arithMul = "*"
+	arithDiv = "/"
+	arithMod = "%"
+	arithPow = "^"
+)
+
+// operand is one side of an element-wise operation, either a series or a
+// scalar broadcast to every position.
+type operand struct {
+	ints     []int64
+	floats   []float64
+	strings  []string
+	isNaN    []bool
+	isNull   []bool
+	typ      string
+	scalar   bool
+	location *time.Location
+}
+
+const (
+	operandInt      = "int"
+	operandFloat    = "float"
+	operandString   = "string"
+	operandDatetime = "datetime"
+	operandDuration = "duration"
+)
+
+func newOperand(value interface{}) (op *operand, err error) {
+	op = new(operand)
+	switch value.(type) {
+	case *Series:
+		se := value.(*Series)
+		op.isNaN, op.isNull = se.IsNaN(), se.IsNull()
+		switch se.array.Elements.(type) {
+		case sint.ElementsInt64:
+			op.ints, op.typ = se.array.Elements.(sint.ElementsInt64).Values(), operandInt
+		case sfloat.ElementsFloat64:
+			op.floats, op.typ = se.array.Elements.(sfloat.ElementsFloat64).Values(), operandFloat
+		case sstring.ElementsString:
+			op.strings, op.typ = se.array.Elements.(sstring.ElementsString).Values(), operandString
+		case sdatetime.ElementsDatetime:
+			elements := se.array.Elements.(sdatetime.ElementsDatetime)
+			op.ints, op.typ, op.location = elements.Values(), operandDatetime, elements.TimeLocation()
+		case sduration.ElementsDuration:
+			op.ints, op.typ = se.array.Elements.(sduration.ElementsDuration).Values(), operandDuration
+		default:
+			err = errors.New(fmt.Sprintf("type %s is not supported", se.Type()))
+		}
+		return
+	case time.Time:
+		timeValue := value.(time.Time)
+		op.ints, op.typ, op.location = []int64{timeValue.UnixNano()}, operandDatetime, timeValue.Location()
+		op.isNaN, op.isNull = []bool{false}, []bool{false}
+	case time.Duration:
+		op.ints, op.typ = []int64{int64(value.(time.Duration))}, operandDuration
+		op.isNaN, op.isNull = []bool{false}, []bool{false}
+	case int, int8, int16, int32, int64:
+		intValue := reflect.ValueOf(value).Int()
+		op.ints, op.typ = []int64{intValue}, operandInt
+		op.isNaN, op.isNull = []bool{false}, []bool{false}
+	case float32, float64:
+		floatValue := reflect.ValueOf(value).Float()
+		op.floats, op.typ = []float64{floatValue}, operandFloat
+		op.isNaN, op.isNull = []bool{math.IsNaN(floatValue)}, []bool{false}
+	case string:
+		stringValue := value.(string)
+		op.strings, op.typ = []string{stringValue}, operandString
+		op.isNaN, op.isNull = []bool{false}, []bool{false}
+	default:
+		err = errors.New(fmt.Sprintf("operand type %s is not supported", reflect.TypeOf(value)))
+		return
+	}
+	op.scalar = true
+	return
+}
+
+func (op *operand) isTime() bool {
+	return op.typ == operandDatetime || op.typ == operandDuration
+}
+
+func (op *operand) isNumber() bool {
+	return op.typ == operandInt || op.typ == operandFloat
+}
+
+func (op *operand) position(i int) int {
+	if op.scalar {
+		return 0
+	}
+	return i
+}
+
+func (op *operand) intAt(i int) int64 {
+	return op.ints[op.position(i)]
+}
+
+func (op *operand) floatAt(i int) float64 {
+	i = op.position(i)
+	if op.typ != operandFloat {
+		return float64(op.ints[i])
+	}
+	return op.floats[i]
+}
+
+func (op *operand) nanAt(i int) bool {
+	return op.isNaN[op.position(i)]
+}
+
+func (op *operand) nullAt(i int) bool {
+	return op.isNull[op.position(i)]
+}
+
+func (se *Series) newOperands(other interface{}) (lhs, rhs *operand, err error) {
+	lhs, err = newOperand(se)
+	if err != nil {
+		return
+	}
+	rhs, err = newOperand(other)
+	if err != nil {
+		return
+	}
+	if !rhs.scalar && len(rhs.isNaN) != se.Len() {
+		err = errors.New(fmt.Sprintf("series length %d doesn't match %d", len(rhs.isNaN), se.Len()))
+	}
+	return
+}
+
+func (se *Series) arithmetic(operator string, other interface{}) (newSeries *Series, err error) {
+	lhs, rhs, err := se.newOperands(other)
+	if err != nil {
+		return
+	}
+	if lhs.typ == operandString || rhs.typ == operandString {
+		err = errors.New(fmt.Sprintf("operator %s is not supported on strings", operator))
+		return
+	}
+	if lhs.isTime() || rhs.isTime() {
+		newSeries, err = se.timeArithmetic(operator, lhs, rhs)
+		return
+	}
+
+	seLen := se.Len()
+	intResult := lhs.typ == operandInt && rhs.typ == operandInt &&
+		operator != arithDiv && operator != arithPow
+	if intResult {
+		result := make([]int64, seLen)
+		isNull := make([]bool, seLen)
+		for i := 0; i < seLen; i++ {
+			if lhs.nanAt(i) || rhs.nanAt(i) {
+				isNull[i] = true
+				continue
+			}
+			a, b := lhs.intAt(i), rhs.intAt(i)
+			switch operator {
+			case arithAdd:
+				result[i] = a + b
+			case arithSub:
+				result[i] = a - b
+			case arithMul:
+				result[i] = a * b
+			case arithMod:
+				if b == 0 {
+					isNull[i] = true
+				} else {
+					result[i] = a % b
+				}
+			}
+		}
+		newSeries = se.newDerivedSeries(sint.NewNullableElementsInt64(result, isNull))
+		return
+	}
+
+	result := make([]float64, seLen)
+	isNull := make([]bool, seLen)
+	for i := 0; i < seLen; i++ {
+		if lhs.nullAt(i) || rhs.nullAt(i) {
+			isNull[i] = true
+			continue
+		}
+		if lhs.nanAt(i) || rhs.nanAt(i) {
+			result[i] = math.NaN()
+			continue
+		}
+		a, b := lhs.floatAt(i), rhs.floatAt(i)
+		switch operator {
+		case arithAdd:
+			result[i] = a + b
+		case arithSub:
+			result[i] = a - b
+		case arithMul:
+			result[i] = a * b
+		case arithDiv:
+			if b == 0 && lhs.typ == operandInt && rhs.typ == operandInt {
+				result[i] = math.NaN()
+			} else {
+				result[i] = a / b
+			}
+		case arithMod:
+			result[i] = math.Mod(a, b)
+		case arithPow:
+			result[i] = math.Pow(a, b)
+		}
+	}
+	newSeries = se.newDerivedSeries(sfloat.NewNullableElementsFloat64(result, isNull))
+	return
+}
+
+// addInt64 returns a + b, ok being false when it overflows.
+func addInt64(a, b int64) (sum int64, ok bool) {
+	sum = a + b
+	return sum, (b >= 0) == (sum >= a)
+}
+
+// subInt64 returns a - b, ok being false when it overflows.
+func subInt64(a, b int64) (difference int64, ok bool) {
+	difference = a - b
+	return difference, (b >= 0) == (difference <= a)
+}
+
+// mulInt64 returns a * b, ok being false when it overflows.
+func mulInt64(a, b int64) (product int64, ok bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	product = a * b
+	return product, product/b == a && !(a == math.MinInt64 && b == -1)
+}
+
+// divRoundInt64 returns a / b rounded half away from zero, ok being false
+// when it overflows. b must not be zero.
+func divRoundInt64(a, b int64) (quotient int64, ok bool) {
+	if a == math.MinInt64 && b == -1 {
+		return
+	}
+	abs := func(x int64) uint64 {
+		if x < 0 {
+			return uint64(-x)
+		}
+		return uint64(x)
+	}
+	quotient, remainder := a/b, a%b
+	if abs(remainder) >= abs(b)-abs(remainder) {
+		if (a < 0) == (b < 0) {
+			quotient++
+		} else {
+			quotient--
+		}
+	}
+	return quotient, true
+}
+
+// roundInt64 rounds f to the nearest int64, ok being false when it's out of
+// range or NaN.
+func roundInt64(f float64) (rounded int64, ok bool) {
+	f = math.Round(f)
+	if !(f >= math.MinInt64 && f < math.MaxInt64) {
+		return
+	}
+	return int64(f), true
+}
+
+// timeArithmetic computes operations with datetime or duration operands:
+// the difference of datetimes is a duration, a datetime plus or minus a
+// duration a datetime, and durations add up, scale by numbers and divide
+// into floats. Dividing by zero gives null. Int factors and divisors are
+// applied in int64, float ones in float64 rounding to the nanosecond, and
+// results out of the int64 range of nanoseconds are an error.
+func (se *Series) timeArithmetic(operator string, lhs, rhs *operand) (newSeries *Series, err error) {
+	additive := operator == arithAdd || operator == arithSub
+	var resultTyp string
+	switch {
+	case operator == arithSub && lhs.typ == operandDatetime && rhs.typ == operandDatetime:
+		resultTyp = operandDuration
+	case additive && lhs.typ == operandDatetime && rhs.typ == operandDuration,
+		operator == arithAdd && lhs.typ == operandDuration && rhs.typ == operandDatetime:
+		resultTyp = operandDatetime
+	case additive && lhs.typ == operandDuration && rhs.typ == operandDuration,
+		operator == arithMul && lhs.typ == operandDuration && rhs.isNumber(),
+		operator == arithMul && lhs.isNumber() && rhs.typ == operandDuration,
+		operator == arithDiv && lhs.typ == operandDuration && rhs.isNumber():
+		resultTyp = operandDuration
+	case operator == arithDiv && lhs.typ == operandDuration && rhs.typ == operandDuration:
+		resultTyp = operandFloat
+	default:
+		err = errors.New(fmt.Sprintf("operator %s is not supported between %s and %s", operator, lhs.typ, rhs.typ))
+		return
+	}
+
+	seLen := se.Len()
+	ints := make([]int64, seLen)
+	floats := make([]float64, seLen)
+	isNull := make([]bool, seLen)
+	for i := 0; i < seLen; i++ {
+		if lhs.nanAt(i) || rhs.nanAt(i) {
+			isNull[i] = true
+			continue
+		}
+		ok := true
+		switch {
+		case operator == arithAdd:
+			ints[i], ok = addInt64(lhs.intAt(i), rhs.intAt(i))
+		case operator == arithSub:
+			ints[i], ok = subInt64(lhs.intAt(i), rhs.intAt(i))
+		case operator == arithMul && lhs.typ != operandFloat && rhs.typ != operandFloat:
+			ints[i], ok = mulInt64(lhs.intAt(i), rhs.intAt(i))
+		case operator == arithMul:
+			ints[i], ok = roundInt64(lhs.floatAt(i) * rhs.floatAt(i))
+		case rhs.floatAt(i) == 0:
+			isNull[i] = true
+		case resultTyp == operandFloat:
+			floats[i] = lhs.floatAt(i) / rhs.floatAt(i)
+		case rhs.typ == operandInt:
+			ints[i], ok = divRoundInt64(lhs.intAt(i), rhs.intAt(i))
+		default:
+			ints[i], ok = roundInt64(lhs.floatAt(i) / rhs.floatAt(i))
+		}
+		if !ok {
+			err = errors.New(fmt.Sprintf("element %d: %s %s %s overflows", i, lhs.typ, operator, rhs.typ))
+			return
+		}
+	}
+
+	switch resultTyp {
+	case operandDatetime:
+		location := lhs.location
+		if location == nil {
+			location = rhs.location
+		}
+		newSeries = se.newDerivedSeries(sdatetime.NewElementsDatetime(ints, isNull, location))
+	case operandDuration:
+		newSeries = se.newDerivedSeries(sduration.NewElementsDuration(ints, isNull))
+	default:
+		newSeries = se.newDerivedSeries(sfloat.NewNullableElementsFloat64(floats, isNull))
+	}
+	return
+}
+
+// Add returns the element-wise sum with another series of the same length
+// or a scalar. Int operands give an int series, any float operand promotes
+// the result to float. Null and NaN elements propagate.
+func (se *Series) Add(other interface{}) (newSeries *Series, err error) {
+	newSeries, err = se.arithmetic(arithAdd, other)
+	if err != nil {
+		err = fmt.Errorf("add error: %w", err)
+	}
+	return
+}
+
+func (se *Series) Sub(other interface{}) (newSeries *Series, err error) {
+	newSeries, err = se.arithmetic(arithSub, other)
+	if err != nil {
+		err = fmt.Errorf("sub error: %w", err)
+	}
+	return
+}
+
+func (se *Series) Mul(other interface{}) (newSeries *Series, err error) {
+	newSeries, err = se.arithmetic(arithMul, other)
+	if err != nil {
+		err = fmt.Errorf("mul error: %w", err)
+	}
+	return
+}
+
+// Div always returns a float series. Dividing an int by an int zero gives
+// NaN, float division follows IEEE 754.
+func (se *Series) Div(other interface{}) (newSeries *Series, err error) {
+	newSeries, err = se.arithmetic(arithDiv, other)
+	if err != nil {
+		err = fmt.Errorf("div error: %w", err)
+	}
+	return
+}
+
+// Mod returns the element-wise remainder. An int modulo zero gives null.
+func (se *Series) Mod(other interface{}) (newSeries *Series, err error) {
+	newSeries, err = se.arithmetic(arithMod, other)
+	if err != nil {
+		err = fmt.Errorf("mod error: %w", err)
+	}
+	return
+}
+
+// Pow always returns a float series.
+func (se *Series) Pow(other interface{}) (newSeries *Series, err error) {
+	newSeries, err = se.arithmetic(arithPow, other)
+	if err != nil {
+		err = fmt.Errorf("pow error: %w", err)
+	}
+	return
+}
+
+// compare evaluates cmp for every position. cmp receives the sign of the
+// comparison of the two operands. Positions with a NaN operand compare to
+// nanResult.
+func (se *Series) compare(other interface{}, nanResult bool, cmp func(sign int) bool) (ixs index.IndexBool, err error) {
+	lhs, rhs, err := se.newOperands(other)
+	if err != nil {
+		return
+	}
+	if (lhs.typ == operandString) != (rhs.typ == operandString) {
+		err = errors.New("can't compare string with number")
+		return
+	}
+	if (lhs.isTime() || rhs.isTime()) && lhs.typ != rhs.typ {
+		err = errors.New(fmt.Sprintf("can't compare %s with %s", lhs.typ, rhs.typ))
+		return
+	}
+
+	seLen := se.Len()
+	ixs = make(index.IndexBool, seLen)
+	for i := 0; i < seLen; i++ {
+		if lhs.nanAt(i) || rhs.nanAt(i) {
+			ixs[i] = nanResult
+			continue
+		}
+		var sign int
+		switch {
+		case lhs.typ == operandString:
+			sign = strings.Compare(lhs.strings[i], rhs.strings[rhs.position(i)])
+		case lhs.typ != operandFloat && rhs.typ != operandFloat:
+			a, b := lhs.intAt(i), rhs.intAt(i)
+			if a < b {
+				sign = -1
+			} else if a > b {
+				sign = 1
+			}
+		default:
+			a, b := lhs.floatAt(i), rhs.floatAt(i)
+			if a < b {
+				sign = -1
+			} else if a > b {
+				sign = 1
+			}
+		}
+		ixs[i] = cmp(sign)
+	}
+	return
+}
+
+// Gt reports for every position whether the element is greater than the
+// element of other, or than other itself when it's a scalar. NaN elements
+// never compare true, except for Ne.
+func (se *Series) Gt(other interface{}) (ixs index.IndexBool, err error) {
+	ixs, err = se.compare(other, false, func(sign int) bool { return sign > 0 })
+	if err != nil {
+		err = fmt.Errorf("gt error: %w", err)
+	}
+	return
+}
+
+func (se *Series) Ge(other interface{}) (ixs index.IndexBool, err error) {
+	ixs, err = se.compare(other, false, func(sign int) bool { return sign >= 0 })
+	if err != nil {
+		err = fmt.Errorf("ge error: %w", err)
+	}
+	return
+}
+
+func (se *Series) Lt(other interface{}) (ixs index.IndexBool, err error) {
+	ixs, err = se.compare(other, false, func(sign int) bool { return sign < 0 })
+	if err != nil {
+		err = fmt.Errorf("lt error: %w", err)
+	}
+	return
+}
+
+func (se *Series) Le(other interface{}) (ixs index.IndexBool, err error) {
+	ixs, err = se.compare(other, false, func(sign int) bool { return sign <= 0 })
+	if err != nil {
+		err = fmt.Errorf("le error: %w", err)
+	}
+	return
+}
+
+func (se *Series) Eq(other interface{}) (ixs index.IndexBool, err error) {
+	ixs, err = se.compare(other, false, func(sign int) bool { return sign == 0 })
+	if err != nil {
+		err = fmt.Errorf("eq error: %w", err)
+	}
+	return
+}
+
+func (se *Series) Ne(other interface{}) (ixs index.IndexBool, err error) {
+	ixs, err = se.compare(other, true, func(sign int) bool { return sign != 0 })
+	if err != nil {
+		err = fmt.Errorf("ne error: %w", err)
+	}
+	return
+}
@@ -0,0 +1,71 @@
+package godas
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSeriesArithmetic(t *testing.T) {
+	price, _ := NewSeries([]float64{1.5, 2, math.NaN()}, "Price")
+	qty, _ := NewSeries([]int{2, 3, 4}, "Qty")
+
+	total, err := price.Mul(qty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{3, 6} {
+		got, _ := total.At(i)
+		if got.MustFloat() != want {
+			t.Errorf("mul at %d: got %v, want %v", i, got.MustFloat(), want)
+		}
+	}
+	if !total.IsNaN()[2] {
+		t.Error("mul should propagate nan")
+	}
+
+	sum, _ := qty.Add(1)
+	got, _ := sum.At(0)
+	if got.MustInt() != 3 {
+		t.Errorf("add scalar: got %v, want 3", got.MustInt())
+	}
+
+	zero, _ := NewSeries([]int{0, 1, 0}, "Zero")
+	quotient, err := qty.Div(zero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	isNaN := quotient.IsNaN()
+	if !isNaN[0] || isNaN[1] || !isNaN[2] {
+		t.Errorf("int division by zero: unexpected nan mask %v", isNaN)
+	}
+	remainder, _ := qty.Mod(zero)
+	if !remainder.IsNaN()[0] {
+		t.Error("int modulo zero should be nan")
+	}
+
+	short, _ := NewSeries([]int{1}, "Short")
+	if _, err := qty.Add(short); err == nil {
+		t.Error("length mismatch should fail")
+	}
+}
+
+func TestSeriesComparison(t *testing.T) {
+	qty, _ := NewSeries([]int{2, 3, 4}, "Qty")
+	ixs, err := qty.Gt(2.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subset, _ := qty.Subset(ixs.IndexInt())
+	if subset.Len() != 2 {
+		t.Errorf("subset by comparison: got %d elements, want 2", subset.Len())
+	}
+
+	names, _ := NewSeries([]string{"b", "a", "NaN"}, "Name")
+	ixs, _ = names.Ne("a")
+	if !ixs[0] || ixs[1] || !ixs[2] {
+		t.Errorf("ne: unexpected result %v", ixs)
+	}
+	if _, err := names.Lt(1); err == nil {
+		t.Error("comparing strings with numbers should fail")
+	}
+}
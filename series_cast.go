@@ -0,0 +1,215 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/internal"
+	"github.com/hunknownz/godas/internal/elements"
+	sbool "github.com/hunknownz/godas/internal/elements_bool"
+	"github.com/hunknownz/godas/internal/elements_composite"
+	sduration "github.com/hunknownz/godas/internal/elements_duration"
+	sfloat "github.com/hunknownz/godas/internal/elements_float"
+	sint "github.com/hunknownz/godas/internal/elements_int"
+	sobject "github.com/hunknownz/godas/internal/elements_object"
+	sstring "github.com/hunknownz/godas/internal/elements_string"
+	"github.com/hunknownz/godas/types"
+	"github.com/spf13/cast"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	CastRaise  = "raise"
+	CastCoerce = "coerce"
+	CastObject = "object"
+)
+
+// AsTypeOptions configures AsType. Errors is the policy for elements that
+// can't be converted: CastRaise, the default, returns an error, CastCoerce
+// makes them null and CastObject returns an object series holding the
+// converted elements and the original ones that failed.
+type AsTypeOptions struct {
+	Errors string
+}
+
+// castValue converts the non missing value to typ. Strings parse as
+// decimal ints, cast would read a leading zero as octal, and as Go or ISO
+// 8601 durations. Ints convert into durations as nanoseconds. Floats only
+// convert into ints when they're whole and in range, cast would truncate
+// them, and numbers convert into bools as being non zero.
+func castValue(typ types.Type, value interface{}) (result interface{}, err error) {
+	switch typ {
+	case types.TypeInt:
+		switch value.(type) {
+		case string:
+			return strconv.ParseInt(value.(string), 10, 64)
+		case time.Duration:
+			return int64(value.(time.Duration)), nil
+		case float64:
+			f := value.(float64)
+			if f != math.Trunc(f) {
+				err = errors.New(fmt.Sprintf("float %v has a fractional part", f))
+				return
+			}
+			if f < math.MinInt64 || f >= math.MaxInt64 {
+				err = errors.New(fmt.Sprintf("float %v is out of the int range", f))
+				return
+			}
+			return int64(f), nil
+		}
+		return cast.ToInt64E(value)
+	case types.TypeFloat:
+		return cast.ToFloat64E(value)
+	case types.TypeBool:
+		switch value.(type) {
+		case int64:
+			return value.(int64) != 0, nil
+		case float64:
+			return value.(float64) != 0, nil
+		}
+		return cast.ToBoolE(value)
+	case types.TypeDatetime:
+		return cast.ToTimeE(value)
+	case types.TypeDuration:
+		if s, ok := value.(string); ok {
+			return internal.ParseDuration(s)
+		}
+		return cast.ToDurationE(value)
+	case types.TypeString:
+		if t, ok := value.(time.Time); ok {
+			return t.Format(time.RFC3339Nano), nil
+		}
+		result, err = cast.ToStringE(value)
+		if err != nil {
+			result, err = fmt.Sprint(value), nil
+		}
+		return
+	default:
+		result = value
+	}
+	return
+}
+
+// newElementsOfType returns elements of type typ holding the values, nil
+// for null, which castValue converted to typ.
+func newElementsOfType(typ types.Type, values []interface{}) (newElements elements.Elements) {
+	isNull := make([]bool, len(values))
+	for i, value := range values {
+		isNull[i] = value == nil
+	}
+	switch typ {
+	case types.TypeInt:
+		typed := make([]int64, len(values))
+		for i, value := range values {
+			if !isNull[i] {
+				typed[i] = value.(int64)
+			}
+		}
+		newElements = sint.NewNullableElementsInt64(typed, isNull)
+	case types.TypeFloat:
+		typed := make([]float64, len(values))
+		for i, value := range values {
+			if !isNull[i] {
+				typed[i] = value.(float64)
+			}
+		}
+		newElements = sfloat.NewNullableElementsFloat64(typed, isNull)
+	case types.TypeBool:
+		typed := make([]bool, len(values))
+		for i, value := range values {
+			if !isNull[i] {
+				typed[i] = value.(bool)
+			}
+		}
+		newElements = sbool.NewNullableElementsBool(typed, isNull)
+	case types.TypeString:
+		typed := make([]string, len(values))
+		for i, value := range values {
+			if !isNull[i] {
+				typed[i] = value.(string)
+			}
+		}
+		newElements = sstring.NewNullableElementsString(typed, isNull)
+	case types.TypeDatetime:
+		typed := make([]*time.Time, len(values))
+		for i, value := range values {
+			if !isNull[i] {
+				t := value.(time.Time)
+				typed[i] = &t
+			}
+		}
+		array, _ := elements_composite.NewArray(typed, "")
+		newElements = array.Elements
+	case types.TypeDuration:
+		typed := make([]int64, len(values))
+		for i, value := range values {
+			if !isNull[i] {
+				typed[i] = int64(value.(time.Duration))
+			}
+		}
+		newElements = sduration.NewElementsDuration(typed, isNull)
+	default:
+		newElements = sobject.NewElementsObject(values)
+	}
+	return
+}
+
+// AsType converts the series to typ. Strings are parsed into ints, floats,
+// bools, datetimes and durations, ints and floats convert into each other and every
+// type formats into strings. Floats with a fractional part or out of the int range
+// can't convert into ints, so the Errors policy applies to them, and ints and floats
+// convert into bools as being non zero. Missing elements stay null, a float NaN
+// converting into a float NaN.
+func (se *Series) AsType(typ types.Type, opts AsTypeOptions) (newSeries *Series, err error) {
+	policy := opts.Errors
+	if policy == "" {
+		policy = CastRaise
+	}
+	if policy != CastRaise && policy != CastCoerce && policy != CastObject {
+		err = errors.New(fmt.Sprintf("as type error: unknown errors policy %q", policy))
+		return
+	}
+	switch typ {
+	case types.TypeInt, types.TypeFloat, types.TypeBool, types.TypeString, types.TypeDatetime, types.TypeDuration, types.TypeObject:
+	default:
+		err = errors.New(fmt.Sprintf("as type error: can't convert to type %s", typ))
+		return
+	}
+	if se.Type() == typ {
+		newSeries = se.Copy()
+		return
+	}
+
+	seLen := se.Len()
+	values := make([]interface{}, seLen)
+	failed := false
+	for i := 0; i < seLen; i++ {
+		element, _ := se.array.Elements.Location(i)
+		if element.IsNaN {
+			if typ == types.TypeFloat && !element.IsNull {
+				values[i] = element.Value
+			}
+			continue
+		}
+		value, e := castValue(typ, element.Value)
+		if e == nil {
+			values[i] = value
+			continue
+		}
+		switch policy {
+		case CastRaise:
+			err = fmt.Errorf("as type error: element %d: %w", i, e)
+			return
+		case CastObject:
+			values[i] = element.Value
+			failed = true
+		}
+	}
+
+	if failed {
+		typ = types.TypeObject
+	}
+	newSeries = se.newDerivedSeries(newElementsOfType(typ, values))
+	return
+}
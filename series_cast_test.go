@@ -0,0 +1,106 @@
+package godas
+
+import (
+	"github.com/hunknownz/godas/types"
+	"math"
+	"testing"
+)
+
+func TestSeriesAsType(t *testing.T) {
+	a, b, c := "010", "2.5", "x"
+	se, _ := NewSeries([]*string{&a, nil, &b, &c}, "value")
+
+	if _, err := se.AsType(types.TypeFloat, AsTypeOptions{}); err == nil {
+		t.Error("raise: expected a parse error")
+	}
+	coerced, err := se.AsType(types.TypeFloat, AsTypeOptions{Errors: CastCoerce})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := coerced.array.Elements.String(); got != "[10 null 2.5 null]" {
+		t.Errorf("coerce: got %s", got)
+	}
+	object, _ := se.AsType(types.TypeFloat, AsTypeOptions{Errors: CastObject})
+	if object.Type() != types.TypeObject {
+		t.Fatalf("object: got type %s", object.Type())
+	}
+	if got, _ := object.At(3); got.Value != "x" {
+		t.Errorf("object: got %v, want the original x", got.Value)
+	}
+	if got, _ := object.At(0); got.Value != float64(10) {
+		t.Errorf("object: got %v, want 10", got.Value)
+	}
+
+	ints, _ := se.AsType(types.TypeInt, AsTypeOptions{Errors: CastCoerce})
+	if got := ints.array.Elements.String(); got != "[10 null null null]" {
+		t.Errorf("int: got %s", got)
+	}
+
+	floats, _ := NewSeries([]float64{1.9, math.NaN()}, "value")
+	if _, err = floats.AsType(types.TypeInt, AsTypeOptions{}); err == nil {
+		t.Error("float to int: expected a fractional part error")
+	}
+	whole, _ := NewSeries([]float64{-3, 1.7, 1e19, math.NaN()}, "value")
+	coercedInts, err := whole.AsType(types.TypeInt, AsTypeOptions{Errors: CastCoerce})
+	if err != nil || coercedInts.array.Elements.String() != "[-3 null null null]" {
+		t.Errorf("float to int coerce: got %v, %v", coercedInts, err)
+	}
+	objectInts, _ := whole.AsType(types.TypeInt, AsTypeOptions{Errors: CastObject})
+	if got, _ := objectInts.At(1); got.Value != 1.7 {
+		t.Errorf("float to int object: got %v, want the original 1.7", got.Value)
+	}
+	if got, _ := objectInts.At(0); got.Value != int64(-3) {
+		t.Errorf("float to int object: got %v, want -3", got.Value)
+	}
+	numbers, _ := NewSeries([]int{0, 2, -1}, "value")
+	numberBools, err := numbers.AsType(types.TypeBool, AsTypeOptions{})
+	if err != nil || numberBools.array.Elements.String() != "[false true true]" {
+		t.Errorf("int to bool: got %v, %v", numberBools, err)
+	}
+	floatBools, err := floats.AsType(types.TypeBool, AsTypeOptions{})
+	if err != nil || floatBools.array.Elements.String() != "[true null]" {
+		t.Errorf("float to bool: got %v, %v", floatBools, err)
+	}
+	strings, _ := floats.AsType(types.TypeString, AsTypeOptions{})
+	if got, _ := strings.At(0); got.Value != "1.9" {
+		t.Errorf("float to string: got %v", got.Value)
+	}
+
+	bools, _ := NewSeries([]string{"true", "0"}, "value")
+	parsed, err := bools.AsType(types.TypeBool, AsTypeOptions{})
+	if err != nil || parsed.array.Elements.String() != "[true false]" {
+		t.Errorf("string to bool: got %v, %v", parsed, err)
+	}
+
+	if _, err = se.AsType(types.TypeComposite, AsTypeOptions{}); err == nil {
+		t.Error("expected an unsupported type error")
+	}
+}
+
+func TestDataFrameAsType(t *testing.T) {
+	type row struct {
+		ID    string
+		Score string
+	}
+	df, _ := NewFromStructs([]row{{"1", "1.5"}, {"2", "n/a"}})
+
+	newDf, err := df.AsType(map[string]types.Type{"ID": types.TypeInt, "Score": types.TypeFloat}, AsTypeOptions{Errors: CastCoerce})
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ := newDf.GetSeriesByColumn("ID")
+	if se.Type() != types.TypeInt {
+		t.Errorf("ID: got type %s", se.Type())
+	}
+	se, _ = newDf.GetSeriesByColumn("Score")
+	if got := se.array.Elements.String(); got != "[1.5 null]" {
+		t.Errorf("Score: got %s", got)
+	}
+
+	if _, err = df.AsType(map[string]types.Type{"Missing": types.TypeInt}, AsTypeOptions{}); err == nil {
+		t.Error("expected an unknown column error")
+	}
+	if _, err = df.AsType(map[string]types.Type{"Score": types.TypeFloat}, AsTypeOptions{}); err == nil {
+		t.Error("expected a parse error")
+	}
+}
@@ -0,0 +1,223 @@
+package godas
+
+import (
+	"github.com/hunknownz/godas/condition"
+	"math"
+	"testing"
+)
+
+func TestSeriesStringCondition(t *testing.T) {
+	se, _ := NewSeries([]string{"Berlin", "bonn", "Paris"}, "City")
+
+	cond := NewSeriesCondition()
+	cond.Or("istartswith", "b").And("!=", "bonn")
+	ixs, err := se.IsCondition(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ixs[0] || ixs[1] || ixs[2] {
+		t.Errorf("unexpected result %v", ixs)
+	}
+
+	name, _ := NewSeries([]string{"a", "b"}, "Name")
+	df, _ := NewFromSeries(name)
+	dfCond := NewDataFrameCondition()
+	dfCond.And("=", "b", "Name")
+	newDataFrame, err := df.Filter(dfCond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newDataFrame.NumRow() != 1 {
+		t.Errorf("filter: got %d rows, want 1", newDataFrame.NumRow())
+	}
+}
+
+func TestSeriesConditionError(t *testing.T) {
+	se, _ := NewSeries([]float64{1, 2.5, 4}, "Value")
+
+	cond := NewSeriesCondition()
+	cond.And(">=", 2).And("<=", 4)
+	ixs, err := se.IsCondition(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ixs[0] || !ixs[1] || !ixs[2] {
+		t.Errorf("unexpected result %v", ixs)
+	}
+
+	cond = NewSeriesCondition()
+	cond.And("~", 2)
+	if _, err := se.IsCondition(cond); err == nil {
+		t.Error("unknown comparator should fail")
+	}
+}
+
+func TestSeriesInAndNaNCondition(t *testing.T) {
+	se, _ := NewSeries([]float64{1, math.NaN(), 3}, "Value")
+
+	cond := NewSeriesCondition()
+	cond.And("is_nan", nil)
+	ixs, err := se.IsCondition(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ixs[0] || !ixs[1] || ixs[2] {
+		t.Errorf("is_nan: unexpected result %v", ixs)
+	}
+
+	cond = NewSeriesCondition()
+	cond.And("not_nan", nil).And("in", []int{3, 4})
+	ixs, _ = se.IsCondition(cond)
+	if ixs[0] || ixs[1] || !ixs[2] {
+		t.Errorf("in: unexpected result %v", ixs)
+	}
+
+	de, it := "DE", "IT"
+	country, _ := NewSeries([]*string{&de, &it, nil}, "Country")
+	df, _ := NewFromSeries(country)
+	dfCond := NewDataFrameCondition()
+	dfCond.And("not in", []string{"DE", "FR"}, "Country").And("not_nan", nil, "Country")
+	newDataFrame, err := df.Filter(dfCond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newDataFrame.NumRow() != 1 {
+		t.Errorf("not in: got %d rows, want 1", newDataFrame.NumRow())
+	}
+}
+
+func TestConditionNegation(t *testing.T) {
+	se, _ := NewSeries([]int{1, 2, 3, 4}, "Value")
+
+	cond := NewSeriesCondition()
+	cond.And(">", 1).AndNot("=", 3)
+	ixs, err := se.IsCondition(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ixs[0] || !ixs[1] || ixs[2] || !ixs[3] {
+		t.Errorf("and not: unexpected result %v", ixs)
+	}
+
+	cond.Not()
+	ixs, _ = se.IsCondition(cond)
+	if !ixs[0] || ixs[1] || !ixs[2] || ixs[3] {
+		t.Errorf("not: unexpected result %v", ixs)
+	}
+
+	name, _ := NewSeries([]string{"a", "b", "c", "d"}, "Name")
+	df, _ := NewFromSeries(se, name)
+	inner := NewDataFrameCondition()
+	inner.And(">=", 2, "Value").And("<=", 3, "Value")
+	dfCond := NewDataFrameCondition()
+	dfCond.AndNotCond(inner).OrNot("!=", "c", "Name")
+	newDataFrame, err := df.Filter(dfCond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newDataFrame.NumRow() != 3 {
+		t.Errorf("df not: got %d rows, want 3", newDataFrame.NumRow())
+	}
+
+	newDataFrame, _ = df.Filter(condition.NotCond(inner))
+	if newDataFrame.NumRow() != 2 {
+		t.Errorf("not cond: got %d rows, want 2", newDataFrame.NumRow())
+	}
+}
+
+func TestConditionFunc(t *testing.T) {
+	se, _ := NewSeries([]int{1, 2, 3, 4, 5}, "Value")
+	odd := func(element condition.ElementValue) bool {
+		value, _ := element.Int()
+		return value%2 == 1
+	}
+
+	cond := NewSeriesCondition()
+	cond.AndFunc("", odd).And(">", 1)
+	newSeries, err := se.Filter(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newSeries.Len() != 2 {
+		t.Errorf("func: got %d elements, want 2", newSeries.Len())
+	}
+
+	cond = NewSeriesCondition()
+	cond.AndRowFunc(func(row condition.Row) bool { return true })
+	if _, err = se.Filter(cond); err == nil {
+		t.Error("row func in a series condition should fail")
+	}
+
+	name, _ := NewSeries([]string{"a", "b", "c", "d", "e"}, "Name")
+	df, _ := NewFromSeries(se, name)
+	dfCond := NewDataFrameCondition()
+	dfCond.AndRowFunc(func(row condition.Row) bool {
+		value, _ := row["Value"].Int()
+		name, _ := row["Name"].String()
+		return value > 3 || name == "a"
+	})
+	inner := NewDataFrameCondition()
+	inner.AndFunc("Value", odd)
+	dfCond.AndCond(inner).Or("=", "b", "Name")
+	newDataFrame, err := df.Filter(dfCond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newDataFrame.NumRow() != 3 {
+		t.Errorf("row func: got %d rows, want 3", newDataFrame.NumRow())
+	}
+}
+
+func TestConditionMissing(t *testing.T) {
+	value, _ := NewSeries([]float64{1, math.NaN(), 3, math.NaN()}, "Value")
+	de, fr := "DE", "FR"
+	country, _ := NewSeries([]*string{&de, nil, &fr, &de}, "Country")
+
+	cond := NewSeriesCondition()
+	cond.And("!=", 1.0)
+	ixs, err := value.IsCondition(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ixs[0] || ixs[1] || !ixs[2] || ixs[3] {
+		t.Errorf("unknown: unexpected result %v", ixs)
+	}
+	cond.Not()
+	ixs, _ = value.IsCondition(cond)
+	if !ixs[0] || ixs[1] || ixs[2] || ixs[3] {
+		t.Errorf("not unknown: unexpected result %v", ixs)
+	}
+	cond.SetMissing(condition.MissingFalse)
+	ixs, _ = value.IsCondition(cond)
+	if !ixs[0] || !ixs[1] || ixs[2] || !ixs[3] {
+		t.Errorf("missing false: unexpected result %v", ixs)
+	}
+
+	df, _ := NewFromSeries(value, country)
+	dfCond := NewDataFrameCondition()
+	dfCond.And(">", 2.0, "Value").Or("=", "DE", "Country")
+	newDataFrame, err := df.Filter(dfCond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newDataFrame.NumRow() != 3 {
+		t.Errorf("or unknown: got %d rows, want 3", newDataFrame.NumRow())
+	}
+
+	dfCond = NewDataFrameCondition()
+	dfCond.AndNot(">", 2.0, "Value").AndNot("=", "FR", "Country")
+	newDataFrame, _ = df.Filter(dfCond)
+	if newDataFrame.NumRow() != 1 {
+		t.Errorf("and unknown: got %d rows, want 1", newDataFrame.NumRow())
+	}
+	dfCond.SetMissing(condition.MissingTrue)
+	newDataFrame, _ = df.Filter(dfCond)
+	if newDataFrame.NumRow() != 1 {
+		t.Errorf("missing true: got %d rows, want 1", newDataFrame.NumRow())
+	}
+	dfCond.SetMissing(condition.MissingFalse)
+	newDataFrame, _ = df.Filter(dfCond)
+	if newDataFrame.NumRow() != 3 {
+		t.Errorf("missing false: got %d rows, want 3", newDataFrame.NumRow())
+	}
+}
@@ -0,0 +1,299 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/internal/elements"
+	ec "github.com/hunknownz/godas/internal/elements_composite"
+	sfloat "github.com/hunknownz/godas/internal/elements_float"
+	sint "github.com/hunknownz/godas/internal/elements_int"
+	"math"
+)
+
+const (
+	cumulativeSum = iota
+	cumulativeProd
+	cumulativeMin
+	cumulativeMax
+)
+
+// CumSum returns the cumulative sum of an int or float series. Null and
+// NaN elements stay missing and are skipped by the running total.
+func (se *Series) CumSum() (newSeries *Series, err error) {
+	newSeries, err = se.cumulate(cumulativeSum, nil)
+	if err != nil {
+		err = fmt.Errorf("cumsum error: %w", err)
+	}
+	return
+}
+
+// CumProd returns the cumulative product of an int or float series.
+func (se *Series) CumProd() (newSeries *Series, err error) {
+	newSeries, err = se.cumulate(cumulativeProd, nil)
+	if err != nil {
+		err = fmt.Errorf("cumprod error: %w", err)
+	}
+	return
+}
+
+// CumMin returns the running minimum of an int or float series.
+func (se *Series) CumMin() (newSeries *Series, err error) {
+	newSeries, err = se.cumulate(cumulativeMin, nil)
+	if err != nil {
+		err = fmt.Errorf("cummin error: %w", err)
+	}
+	return
+}
+
+// CumMax returns the running maximum of an int or float series.
+func (se *Series) CumMax() (newSeries *Series, err error) {
+	newSeries, err = se.cumulate(cumulativeMax, nil)
+	if err != nil {
+		err = fmt.Errorf("cummax error: %w", err)
+	}
+	return
+}
+
+// Shift moves the elements by periods positions, forwards for a positive
+// periods and backwards for a negative one. Positions shifted in are null.
+func (se *Series) Shift(periods int) (newSeries *Series, err error) {
+	newSeries, err = se.shift(periods, nil)
+	if err != nil {
+		err = fmt.Errorf("shift error: %w", err)
+	}
+	return
+}
+
+// Diff returns the difference between each element and the element periods
+// positions before it.
+func (se *Series) Diff(periods int) (newSeries *Series, err error) {
+	newSeries, err = se.diff(periods, nil)
+	if err != nil {
+		err = fmt.Errorf("diff error: %w", err)
+	}
+	return
+}
+
+// PctChange returns the float fractional change between each element and
+// the element periods positions before it.
+func (se *Series) PctChange(periods int) (newSeries *Series, err error) {
+	newSeries, err = se.pctChange(periods, nil)
+	if err != nil {
+		err = fmt.Errorf("pct change error: %w", err)
+	}
+	return
+}
+
+func (se *Series) rowGroups(groups [][]int) [][]int {
+	if groups != nil {
+		return groups
+	}
+	seLen := se.Len()
+	rows := make([]int, seLen)
+	for i := 0; i < seLen; i++ {
+		rows[i] = i
+	}
+	return [][]int{rows}
+}
+
+func (se *Series) newDerivedSeries(newElements elements.Elements) *Series {
+	return &Series{
+		array: &ec.Array{
+			FieldName: se.array.FieldName,
+			Elements:  newElements,
+		},
+	}
+}
+
+func cumulateInt(kind int, acc, value int64) int64 {
+	switch kind {
+	case cumulativeSum:
+		return acc + value
+	case cumulativeProd:
+		return acc * value
+	case cumulativeMin:
+		if value < acc {
+			return value
+		}
+	case cumulativeMax:
+		if value > acc {
+			return value
+		}
+	}
+	return acc
+}
+
+func cumulateFloat(kind int, acc, value float64) float64 {
+	switch kind {
+	case cumulativeSum:
+		return acc + value
+	case cumulativeProd:
+		return acc * value
+	case cumulativeMin:
+		return math.Min(acc, value)
+	case cumulativeMax:
+		return math.Max(acc, value)
+	}
+	return acc
+}
+
+func (se *Series) cumulate(kind int, groups [][]int) (newSeries *Series, err error) {
+	isNaN, isNull := se.IsNaN(), se.IsNull()
+	var newElements elements.Elements
+	switch se.array.Elements.(type) {
+	case sint.ElementsInt64:
+		values := se.array.Elements.(sint.ElementsInt64).Values()
+		result := make([]int64, len(values))
+		for _, rows := range se.rowGroups(groups) {
+			var acc int64
+			started := false
+			for _, row := range rows {
+				if isNaN[row] {
+					continue
+				}
+				if !started {
+					acc, started = values[row], true
+				} else {
+					acc = cumulateInt(kind, acc, values[row])
+				}
+				result[row] = acc
+			}
+		}
+		newElements = sint.NewNullableElementsInt64(result, isNull)
+	case sfloat.ElementsFloat64:
+		values := se.array.Elements.(sfloat.ElementsFloat64).Values()
+		result := make([]float64, len(values))
+		for _, rows := range se.rowGroups(groups) {
+			var acc float64
+			started := false
+			for _, row := range rows {
+				if isNaN[row] {
+					result[row] = math.NaN()
+					continue
+				}
+				if !started {
+					acc, started = values[row], true
+				} else {
+					acc = cumulateFloat(kind, acc, values[row])
+				}
+				result[row] = acc
+			}
+		}
+		newElements = sfloat.NewNullableElementsFloat64(result, isNull)
+	default:
+		err = errors.New(fmt.Sprintf("type %s is not supported", se.Type()))
+		return
+	}
+
+	newSeries = se.newDerivedSeries(newElements)
+	return
+}
+
+func (se *Series) shift(periods int, groups [][]int) (newSeries *Series, err error) {
+	seIsNull := se.IsNull()
+	isNull := make([]bool, len(seIsNull))
+	var newElements elements.Elements
+	switch se.array.Elements.(type) {
+	case sint.ElementsInt64:
+		values := se.array.Elements.(sint.ElementsInt64).Values()
+		result := make([]int64, len(values))
+		for _, rows := range se.rowGroups(groups) {
+			for k, row := range rows {
+				from := k - periods
+				if from < 0 || from >= len(rows) {
+					isNull[row] = true
+					continue
+				}
+				result[row], isNull[row] = values[rows[from]], seIsNull[rows[from]]
+			}
+		}
+		newElements = sint.NewNullableElementsInt64(result, isNull)
+	case sfloat.ElementsFloat64:
+		values := se.array.Elements.(sfloat.ElementsFloat64).Values()
+		result := make([]float64, len(values))
+		for _, rows := range se.rowGroups(groups) {
+			for k, row := range rows {
+				from := k - periods
+				if from < 0 || from >= len(rows) {
+					isNull[row] = true
+					continue
+				}
+				result[row], isNull[row] = values[rows[from]], seIsNull[rows[from]]
+			}
+		}
+		newElements = sfloat.NewNullableElementsFloat64(result, isNull)
+	default:
+		err = errors.New(fmt.Sprintf("type %s is not supported", se.Type()))
+		return
+	}
+
+	newSeries = se.newDerivedSeries(newElements)
+	return
+}
+
+func (se *Series) diff(periods int, groups [][]int) (newSeries *Series, err error) {
+	seIsNull := se.IsNull()
+	isNull := make([]bool, len(seIsNull))
+	var newElements elements.Elements
+	switch se.array.Elements.(type) {
+	case sint.ElementsInt64:
+		values := se.array.Elements.(sint.ElementsInt64).Values()
+		result := make([]int64, len(values))
+		for _, rows := range se.rowGroups(groups) {
+			for k, row := range rows {
+				from := k - periods
+				if from < 0 || from >= len(rows) || seIsNull[row] || seIsNull[rows[from]] {
+					isNull[row] = true
+					continue
+				}
+				result[row] = values[row] - values[rows[from]]
+			}
+		}
+		newElements = sint.NewNullableElementsInt64(result, isNull)
+	case sfloat.ElementsFloat64:
+		values := se.array.Elements.(sfloat.ElementsFloat64).Values()
+		result := make([]float64, len(values))
+		for _, rows := range se.rowGroups(groups) {
+			for k, row := range rows {
+				from := k - periods
+				if from < 0 || from >= len(rows) || seIsNull[row] || seIsNull[rows[from]] {
+					isNull[row] = true
+					continue
+				}
+				result[row] = values[row] - values[rows[from]]
+			}
+		}
+		newElements = sfloat.NewNullableElementsFloat64(result, isNull)
+	default:
+		err = errors.New(fmt.Sprintf("type %s is not supported", se.Type()))
+		return
+	}
+
+	newSeries = se.newDerivedSeries(newElements)
+	return
+}
+
+func (se *Series) pctChange(periods int, groups [][]int) (newSeries *Series, err error) {
+	values, _, err := se.floatValues()
+	if err != nil {
+		err = errors.New(fmt.Sprintf("type %s is not supported", se.Type()))
+		return
+	}
+
+	seIsNull := se.IsNull()
+	isNull := make([]bool, len(seIsNull))
+	result := make([]float64, len(values))
+	for _, rows := range se.rowGroups(groups) {
+		for k, row := range rows {
+			from := k - periods
+			if from < 0 || from >= len(rows) || seIsNull[row] || seIsNull[rows[from]] {
+				isNull[row] = true
+				continue
+			}
+			result[row] = values[row]/values[rows[from]] - 1
+		}
+	}
+
+	newSeries = se.newDerivedSeries(sfloat.NewNullableElementsFloat64(result, isNull))
+	return
+}
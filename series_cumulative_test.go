@@ -0,0 +1,109 @@
+package godas
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSeriesCumulative(t *testing.T) {
+	se, _ := NewSeries([]float64{1, 2, math.NaN(), 4}, "value")
+
+	cumSum, err := se.CumSum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{1, 3, math.NaN(), 7}
+	for i, want := range expected {
+		got, _ := cumSum.At(i)
+		value := got.MustFloat()
+		if math.IsNaN(want) != math.IsNaN(value) || (!math.IsNaN(want) && value != want) {
+			t.Errorf("cumsum at %d: got %v, want %v", i, value, want)
+		}
+	}
+
+	seInt, _ := NewSeries([]int{3, 1, 2}, "value")
+	cumMax, _ := seInt.CumMax()
+	for i, want := range []int64{3, 3, 3} {
+		got, _ := cumMax.At(i)
+		if got.MustInt() != want {
+			t.Errorf("cummax at %d: got %v, want %v", i, got.MustInt(), want)
+		}
+	}
+}
+
+func TestSeriesShiftDiff(t *testing.T) {
+	se, _ := NewSeries([]int{1, 4, 9, 16}, "value")
+
+	shifted, _ := se.Shift(1)
+	isNaN := shifted.IsNaN()
+	if !isNaN[0] || isNaN[1] {
+		t.Errorf("shift: unexpected nan mask %v", isNaN)
+	}
+
+	diff, _ := se.Diff(1)
+	for i, want := range []int64{4 - 1, 9 - 4, 16 - 9} {
+		got, _ := diff.At(i + 1)
+		if got.MustInt() != want {
+			t.Errorf("diff at %d: got %v, want %v", i+1, got.MustInt(), want)
+		}
+	}
+
+	pct, _ := se.PctChange(1)
+	got, _ := pct.At(1)
+	if got.MustFloat() != 3 {
+		t.Errorf("pct change at 1: got %v, want 3", got.MustFloat())
+	}
+
+	if _, err := se.Shift(-1); err != nil {
+		t.Error(err)
+	}
+	seString, _ := NewSeries([]string{"a"}, "value")
+	if _, err := seString.Diff(1); err == nil {
+		t.Error("diff on string series should fail")
+	}
+}
+
+func TestDataFrameGroupDiff(t *testing.T) {
+	user, _ := NewSeries([]string{"a", "b", "a", "b"}, "User")
+	value, _ := NewSeries([]int{1, 10, 3, 15}, "Value")
+	df, _ := NewFromSeries(user, value)
+
+	diff, err := df.Diff(1, "User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedNaN := []bool{true, true, false, false}
+	expected := []int64{0, 0, 2, 5}
+	for i := range expected {
+		got, _ := diff.At(i, "Value")
+		if expectedNaN[i] {
+			if !got.Null() {
+				t.Errorf("diff at %d: expected null, got %v", i, got.MustInt())
+			}
+			continue
+		}
+		if got.MustInt() != expected[i] {
+			t.Errorf("diff at %d: got %v, want %v", i, got.MustInt(), expected[i])
+		}
+	}
+	got, _ := diff.At(2, "User")
+	if got.MustString() != "a" {
+		t.Errorf("group column changed: %v", got.MustString())
+	}
+}
+
+func TestDataFrameGroupNullKey(t *testing.T) {
+	zero := 0
+	group, _ := NewSeries([]*int{nil, &zero, nil}, "G")
+	value, _ := NewSeries([]int{1, 2, 4}, "V")
+	df, _ := NewFromSeries(group, value)
+
+	cumSum, err := df.CumSum("G")
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ := cumSum.GetSeriesByColumn("V")
+	if got := se.array.Elements.String(); got != "[1 2 5]" {
+		t.Errorf("cumsum: got %s, want nulls grouped apart from zeros", got)
+	}
+}
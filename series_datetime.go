@@ -0,0 +1,145 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	ec "github.com/hunknownz/godas/internal/elements_composite"
+	sdatetime "github.com/hunknownz/godas/internal/elements_datetime"
+	sint "github.com/hunknownz/godas/internal/elements_int"
+	sstring "github.com/hunknownz/godas/internal/elements_string"
+	"github.com/hunknownz/godas/types"
+	"time"
+)
+
+// DatetimeAccessor reads the calendar fields of a datetime series in the
+// location of the series. Null elements stay null.
+type DatetimeAccessor struct {
+	se       *Series
+	elements sdatetime.ElementsDatetime
+}
+
+// Dt returns the datetime accessor of a datetime series.
+func (se *Series) Dt() (accessor *DatetimeAccessor, err error) {
+	elements, ok := se.array.Elements.(sdatetime.ElementsDatetime)
+	if !ok {
+		err = errors.New(fmt.Sprintf("dt error: type %s is not datetime", se.Type()))
+		return
+	}
+	accessor = &DatetimeAccessor{
+		se:       se,
+		elements: elements,
+	}
+	return
+}
+
+func (accessor *DatetimeAccessor) intField(f func(t time.Time) int) *Series {
+	elements := accessor.elements
+	isNull := elements.IsNull()
+	values := make([]int64, elements.Len())
+	for i := range values {
+		if !isNull[i] {
+			values[i] = int64(f(elements.Time(i)))
+		}
+	}
+	return accessor.se.newDerivedSeries(sint.NewNullableElementsInt64(values, isNull))
+}
+
+func (accessor *DatetimeAccessor) Year() *Series {
+	return accessor.intField(time.Time.Year)
+}
+
+// Month returns the months, January being 1.
+func (accessor *DatetimeAccessor) Month() *Series {
+	return accessor.intField(func(t time.Time) int {
+		return int(t.Month())
+	})
+}
+
+func (accessor *DatetimeAccessor) Day() *Series {
+	return accessor.intField(time.Time.Day)
+}
+
+// Weekday returns the days of the week, Sunday being 0.
+func (accessor *DatetimeAccessor) Weekday() *Series {
+	return accessor.intField(func(t time.Time) int {
+		return int(t.Weekday())
+	})
+}
+
+func (accessor *DatetimeAccessor) Hour() *Series {
+	return accessor.intField(time.Time.Hour)
+}
+
+// Truncate rounds the datetimes down to a multiple of d since the zero
+// time, like time.Time.Truncate.
+func (accessor *DatetimeAccessor) Truncate(d time.Duration) *Series {
+	elements := accessor.elements
+	isNull := elements.IsNull()
+	values := make([]int64, elements.Len())
+	for i := range values {
+		if !isNull[i] {
+			values[i] = elements.Time(i).Truncate(d).UnixNano()
+		}
+	}
+	newElements := sdatetime.NewElementsDatetime(values, isNull, elements.TimeLocation())
+	return accessor.se.newDerivedSeries(newElements)
+}
+
+// Format formats the datetimes with layout into a string series.
+func (accessor *DatetimeAccessor) Format(layout string) *Series {
+	elements := accessor.elements
+	isNull := elements.IsNull()
+	values := make([]string, elements.Len())
+	for i := range values {
+		if !isNull[i] {
+			values[i] = elements.Time(i).Format(layout)
+		}
+	}
+	return accessor.se.newDerivedSeries(sstring.NewNullableElementsString(values, isNull))
+}
+
+// parseDatetimes parses the strings values, nil for null, with layout in
+// location, UTC when nil.
+func parseDatetimes(values []*string, layout string, location *time.Location) (newElements sdatetime.ElementsDatetime, err error) {
+	if location == nil {
+		location = time.UTC
+	}
+	nanos := make([]int64, len(values))
+	isNull := make([]bool, len(values))
+	for i, value := range values {
+		if value == nil {
+			isNull[i] = true
+			continue
+		}
+		t, e := time.ParseInLocation(layout, *value, location)
+		if e != nil {
+			err = fmt.Errorf("parse %s error: %w", types.TypeDatetime, e)
+			return
+		}
+		nanos[i] = t.UnixNano()
+	}
+	newElements = sdatetime.NewElementsDatetime(nanos, isNull, location)
+	return
+}
+
+// DateRange returns a datetime series from start up to end, excluded,
+// every step, the way internal.GenerateSequenceInt counts ints. The
+// datetimes are in the location of start.
+func DateRange(start, end time.Time, step time.Duration, fieldName string) (se *Series, err error) {
+	if step == 0 {
+		err = errors.New("date range error: step must not be zero")
+		return
+	}
+	var values []int64
+	begin, stop := start.UnixNano(), end.UnixNano()
+	for value := begin; (step > 0 && value < stop) || (step < 0 && value > stop); value += int64(step) {
+		values = append(values, value)
+	}
+	se = &Series{
+		array: &ec.Array{
+			FieldName: fieldName,
+			Elements:  sdatetime.NewElementsDatetime(values, nil, start.Location()),
+		},
+	}
+	return
+}
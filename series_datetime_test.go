@@ -0,0 +1,140 @@
+package godas
+
+import (
+	"github.com/hunknownz/godas/condition"
+	"github.com/hunknownz/godas/types"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSeriesDatetime(t *testing.T) {
+	location := time.FixedZone("UTC+8", 8*60*60)
+	day := time.Date(2020, time.March, 1, 22, 30, 0, 0, location)
+	before := day.Add(-time.Hour)
+	se, err := NewSeries([]*time.Time{&day, nil, &before}, "At")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if se.Type() != types.TypeDatetime {
+		t.Fatalf("got type %s", se.Type())
+	}
+	if got, _ := se.At(0); !got.MustTime().Equal(day) || got.MustTime().Location() != location {
+		t.Errorf("at: got %v, want %v", got.MustTime(), day)
+	}
+
+	sorted, _ := se.Sort(false, true)
+	if got := sorted.array.Elements.String(); got != "[null 2020-03-01T21:30:00+08:00 2020-03-01T22:30:00+08:00]" {
+		t.Errorf("sort: got %s", got)
+	}
+
+	cond := NewSeriesCondition()
+	cond.Or(condition.ComparatorGT, day.Add(-time.Minute).UTC())
+	ixs, err := se.IsCondition(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]bool(ixs), []bool{true, false, false}) {
+		t.Errorf("condition: got %v", ixs)
+	}
+
+	dt, err := se.Dt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dt.Year().array.Elements.String(); got != "[2020 null 2020]" {
+		t.Errorf("year: got %s", got)
+	}
+	if got := dt.Month().array.Elements.String(); got != "[3 null 3]" {
+		t.Errorf("month: got %s", got)
+	}
+	if got := dt.Day().array.Elements.String(); got != "[1 null 1]" {
+		t.Errorf("day: got %s", got)
+	}
+	if got := dt.Weekday().array.Elements.String(); got != "[0 null 0]" {
+		t.Errorf("weekday: got %s", got)
+	}
+	if got := dt.Hour().array.Elements.String(); got != "[22 null 21]" {
+		t.Errorf("hour: got %s", got)
+	}
+	if got := dt.Truncate(time.Hour).array.Elements.String(); got != "[2020-03-01T22:00:00+08:00 null 2020-03-01T21:00:00+08:00]" {
+		t.Errorf("truncate: got %s", got)
+	}
+	if got := dt.Format("2006-01-02 15h").array.Elements.String(); got != "[2020-03-01 22h null 2020-03-01 21h]" {
+		t.Errorf("format: got %s", got)
+	}
+
+	ints, _ := NewSeries([]int{1}, "value")
+	if _, err = ints.Dt(); err == nil {
+		t.Error("dt: expected a type error")
+	}
+}
+
+func TestDataFrameDatetime(t *testing.T) {
+	type event struct {
+		Name string
+		At   time.Time
+		End  *time.Time
+	}
+	at := time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC)
+	df, err := NewFromStructs([]event{{"b", at, nil}, {"a", at.Add(-time.Hour), &at}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ := df.GetSeriesByColumn("End")
+	if se.Type() != types.TypeDatetime || !reflect.DeepEqual(se.IsNull(), []bool{true, false}) {
+		t.Errorf("struct reader: got %s series with null mask %v", se.Type(), se.IsNull())
+	}
+	if got := df.ToStructs()[1].(*event); got.End == nil || !got.End.Equal(at) || !got.At.Equal(at.Add(-time.Hour)) {
+		t.Errorf("struct writer: got %+v", got)
+	}
+	sorted, _ := df.Sort(false, SortKey{Column: "At", Ascending: true})
+	se, _ = sorted.GetSeriesByColumn("Name")
+	if got := se.array.Elements.String(); got != "[a b]" {
+		t.Errorf("sort: got %s", got)
+	}
+
+	query, err := df.Query("At < '2020-01-02T00:00:00Z'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.NumRow() != 1 {
+		t.Errorf("query: got %d rows, want 1", query.NumRow())
+	}
+
+	file, err := ioutil.TempFile("", "godas-*.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("Day,Value\n2020/01/02,1\n,2\n")
+	file.Close()
+	df, err = NewFromCSV(file.Name(), CSVOptions{DatetimeLayouts: map[string]string{"Day": "2006/01/02"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ = df.GetSeriesByColumn("Day")
+	if got := se.array.Elements.String(); got != "[2020-01-02T00:00:00Z null]" {
+		t.Errorf("csv reader: got %s", got)
+	}
+	if _, err = NewFromCSV(file.Name(), CSVOptions{DatetimeLayouts: map[string]string{"Value": time.RFC3339}}); err == nil {
+		t.Error("csv reader: expected a parse error")
+	}
+}
+
+func TestSeriesDatetimeFillNA(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	se, _ := NewSeries([]*time.Time{&start, nil}, "At")
+	filled, err := se.FillNA(start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filled.array.Elements.String(); got != "[2020-01-01T00:00:00Z 2020-01-01T01:00:00Z]" {
+		t.Errorf("fill na: got %s", got)
+	}
+	if _, err = se.FillNA("2020-01-01"); err == nil {
+		t.Error("expected a fill value type error")
+	}
+}
@@ -0,0 +1,146 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/internal"
+	sduration "github.com/hunknownz/godas/internal/elements_duration"
+	sfloat "github.com/hunknownz/godas/internal/elements_float"
+	"math"
+	"time"
+)
+
+// DurationAccessor aggregates and converts the elements of a duration
+// series. Null elements are skipped by the aggregations unless skipNaN is
+// false, in which case any null makes them fail.
+type DurationAccessor struct {
+	se       *Series
+	elements sduration.ElementsDuration
+}
+
+// Td returns the duration accessor of a duration series.
+func (se *Series) Td() (accessor *DurationAccessor, err error) {
+	elements, ok := se.array.Elements.(sduration.ElementsDuration)
+	if !ok {
+		err = errors.New(fmt.Sprintf("td error: type %s is not duration", se.Type()))
+		return
+	}
+	accessor = &DurationAccessor{
+		se:       se,
+		elements: elements,
+	}
+	return
+}
+
+func (accessor *DurationAccessor) reduce(name string, skipNaN []bool, f func(values []float64) float64) (d time.Duration, err error) {
+	result, err := accessor.se.reduce(name, skipNaN, f)
+	if err != nil {
+		return
+	}
+	if math.IsNaN(result) {
+		err = errors.New(fmt.Sprintf("%s error: no durations to aggregate", name))
+		return
+	}
+	d = time.Duration(math.Round(result))
+	return
+}
+
+// sum adds up the durations in int64 nanoseconds, returning their number
+// too, and fails when the sum overflows.
+func (accessor *DurationAccessor) sum(name string, skipNaN []bool) (total int64, n int, err error) {
+	validity := accessor.elements.Validity()
+	for i, value := range accessor.elements.Values() {
+		if !validity.IsValid(i) {
+			if !checkSkipNaN(skipNaN) {
+				err = errors.New(fmt.Sprintf("%s error: element %d is null", name, i))
+				return
+			}
+			continue
+		}
+		if (value > 0 && total > math.MaxInt64-value) || (value < 0 && total < math.MinInt64-value) {
+			err = errors.New(fmt.Sprintf("%s error: durations overflow", name))
+			return
+		}
+		total += value
+		n++
+	}
+	return
+}
+
+func (accessor *DurationAccessor) Sum(skipNaN ...bool) (d time.Duration, err error) {
+	total, _, err := accessor.sum("sum", skipNaN)
+	d = time.Duration(total)
+	return
+}
+
+// Mean divides the sum of the durations by their number, rounding half
+// away from zero.
+func (accessor *DurationAccessor) Mean(skipNaN ...bool) (d time.Duration, err error) {
+	total, n, err := accessor.sum("mean", skipNaN)
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		err = errors.New("mean error: no durations to aggregate")
+		return
+	}
+	quotient, _ := divRoundInt64(total, int64(n))
+	d = time.Duration(quotient)
+	return
+}
+
+func (accessor *DurationAccessor) Median(skipNaN ...bool) (time.Duration, error) {
+	return accessor.Quantile(0.5, skipNaN...)
+}
+
+func (accessor *DurationAccessor) Min(skipNaN ...bool) (time.Duration, error) {
+	return accessor.Quantile(0, skipNaN...)
+}
+
+func (accessor *DurationAccessor) Max(skipNaN ...bool) (time.Duration, error) {
+	return accessor.Quantile(1, skipNaN...)
+}
+
+// Quantile returns the q-th quantile of the durations, such as 0.99 for
+// the 99th percentile, interpolating linearly between the closest ranks.
+func (accessor *DurationAccessor) Quantile(q float64, skipNaN ...bool) (d time.Duration, err error) {
+	if q < 0 || q > 1 {
+		err = errors.New(fmt.Sprintf("quantile error: q %v must be in [0, 1]", q))
+		return
+	}
+	return accessor.reduce("quantile", skipNaN, func(values []float64) float64 {
+		return quantile(values, q)
+	})
+}
+
+// Seconds returns the durations in seconds as a float series.
+func (accessor *DurationAccessor) Seconds() *Series {
+	elements := accessor.elements
+	isNull := elements.IsNull()
+	values := make([]float64, elements.Len())
+	for i, value := range elements.Values() {
+		values[i] = time.Duration(value).Seconds()
+	}
+	return accessor.se.newDerivedSeries(sfloat.NewNullableElementsFloat64(values, isNull))
+}
+
+// parseDurations parses the strings values, nil for null, as Go or ISO
+// 8601 durations.
+func parseDurations(values []*string) (newElements sduration.ElementsDuration, err error) {
+	nanos := make([]int64, len(values))
+	isNull := make([]bool, len(values))
+	for i, value := range values {
+		if value == nil {
+			isNull[i] = true
+			continue
+		}
+		d, e := internal.ParseDuration(*value)
+		if e != nil {
+			err = e
+			return
+		}
+		nanos[i] = int64(d)
+	}
+	newElements = sduration.NewElementsDuration(nanos, isNull)
+	return
+}
@@ -0,0 +1,239 @@
+package godas
+
+import (
+	"github.com/hunknownz/godas/condition"
+	"github.com/hunknownz/godas/internal"
+	"github.com/hunknownz/godas/types"
+	"io/ioutil"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"1h30m":          90 * time.Minute,
+		"P1DT2H":         26 * time.Hour,
+		"PT0.5S":         500 * time.Millisecond,
+		"P1W":            7 * 24 * time.Hour,
+		"-PT1M1.000001S": -(time.Minute + time.Second + time.Microsecond),
+		"P106751DT23H47M16.854775807S": math.MaxInt64,
+	}
+	for s, want := range cases {
+		got, err := internal.ParseDuration(s)
+		if err != nil || got != want {
+			t.Errorf("parse %q: got %v, %v, want %v", s, got, err, want)
+		}
+	}
+	invalid := []string{"P1M", "P", "PT", "P1DT", "1 hour", "P999999999D", "P15251W", "P106751DT23H47M16.854775808S", "PT2562047H47M16.854775808S"}
+	for _, s := range invalid {
+		if _, err := internal.ParseDuration(s); err == nil {
+			t.Errorf("parse %q: expected an error", s)
+		}
+	}
+}
+
+func TestSeriesDuration(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	starts, _ := NewSeries([]time.Time{start, start, start}, "Start")
+	end1, end2 := start.Add(2*time.Second), start.Add(90*time.Minute)
+	ends, _ := NewSeries([]*time.Time{&end1, nil, &end2}, "End")
+
+	latency, err := ends.Sub(starts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latency.Type() != types.TypeDuration {
+		t.Fatalf("sub: got type %s", latency.Type())
+	}
+	if got := latency.array.Elements.String(); got != "[2s null 1h30m0s]" {
+		t.Errorf("sub: got %s", got)
+	}
+
+	shifted, _ := starts.Add(time.Hour)
+	if got, _ := shifted.At(0); !got.MustTime().Equal(start.Add(time.Hour)) {
+		t.Errorf("datetime add: got %v", got.MustTime())
+	}
+	doubled, _ := latency.Mul(2)
+	if got := doubled.array.Elements.String(); got != "[4s null 3h0m0s]" {
+		t.Errorf("duration mul: got %s", got)
+	}
+	ratio, _ := latency.Div(time.Second)
+	if got, _ := ratio.At(2); got.MustFloat() != 5400 {
+		t.Errorf("duration div: got %v", got.MustFloat())
+	}
+	if _, err = starts.Add(starts); err == nil {
+		t.Error("datetime add: expected an error")
+	}
+	if _, err = latency.Gt(1); err == nil {
+		t.Error("gt: expected a type error")
+	}
+	slow, _ := latency.Gt(time.Minute)
+	if !reflect.DeepEqual([]bool(slow), []bool{false, false, true}) {
+		t.Errorf("gt: got %v", slow)
+	}
+
+	td, err := latency.Td()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := td.Mean(); got != 45*time.Minute+time.Second {
+		t.Errorf("mean: got %v", got)
+	}
+	if got, _ := td.Quantile(1); got != 90*time.Minute {
+		t.Errorf("quantile: got %v", got)
+	}
+	if _, err = td.Mean(false); err == nil {
+		t.Error("mean: expected an error with nulls kept")
+	}
+	if got, _ := td.Seconds().At(0); got.MustFloat() != 2 {
+		t.Errorf("seconds: got %v", got.MustFloat())
+	}
+
+	cond := NewSeriesCondition()
+	cond.Or(condition.ComparatorLT, "PT1M")
+	ixs, err := latency.IsCondition(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]bool(ixs), []bool{true, false, false}) {
+		t.Errorf("condition: got %v", ixs)
+	}
+
+	strings, _ := latency.AsType(types.TypeString, AsTypeOptions{})
+	if got, _ := strings.At(2); got.Value != "1h30m0s" {
+		t.Errorf("as string: got %v", got.Value)
+	}
+	parsed, err := strings.AsType(types.TypeDuration, AsTypeOptions{})
+	if err != nil || !reflect.DeepEqual(parsed.array.Elements, latency.array.Elements) {
+		t.Errorf("as duration: got %v, %v", parsed, err)
+	}
+}
+
+func TestSeriesDurationSumMean(t *testing.T) {
+	big := time.Duration(1<<53 + 1)
+	se, _ := NewSeries([]time.Duration{big, big}, "value")
+	td, _ := se.Td()
+	if got, err := td.Sum(); err != nil || got != 2*big {
+		t.Errorf("sum: got %v, %v, want %v", got, err, 2*big)
+	}
+	if got, err := td.Mean(); err != nil || got != big {
+		t.Errorf("mean: got %v, %v, want %v", int64(got), err, int64(big))
+	}
+
+	se, _ = NewSeries([]time.Duration{-1, -2}, "value")
+	td, _ = se.Td()
+	if got, _ := td.Mean(); got != -2 {
+		t.Errorf("mean rounding: got %v, want -2ns", got)
+	}
+
+	se, _ = NewSeries([]time.Duration{math.MaxInt64, 1}, "value")
+	td, _ = se.Td()
+	if _, err := td.Sum(); err == nil {
+		t.Error("sum: expected an overflow error")
+	}
+	if _, err := td.Mean(); err == nil {
+		t.Error("mean: expected an overflow error")
+	}
+}
+
+func TestSeriesDurationArithmeticOverflow(t *testing.T) {
+	big := time.Duration(1<<53 + 1)
+	se, _ := NewSeries([]time.Duration{big, 5, -5}, "value")
+	tripled, err := se.Mul(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := tripled.At(0); got.Value != 3*big {
+		t.Errorf("mul: got %v, want %v", got.Value, 3*big)
+	}
+	halved, _ := se.Div(2)
+	if got := halved.array.Elements.String(); got != "[1250h59m59.627370497s 3ns -3ns]" {
+		t.Errorf("div: got %s", got)
+	}
+
+	if _, err = se.Mul(1 << 11); err == nil {
+		t.Error("mul int: expected an overflow error")
+	}
+	if _, err = se.Mul(1e300); err == nil {
+		t.Error("mul float: expected an overflow error")
+	}
+	if _, err = se.Div(1e-300); err == nil {
+		t.Error("div float: expected an overflow error")
+	}
+	if _, err = se.Mul(math.Inf(1)); err == nil {
+		t.Error("mul inf: expected an overflow error")
+	}
+
+	early := time.Date(1700, time.January, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2200, time.January, 1, 0, 0, 0, 0, time.UTC)
+	dates, _ := NewSeries([]time.Time{late}, "value")
+	if _, err = dates.Sub(early); err == nil {
+		t.Error("sub: expected an overflow error")
+	}
+}
+
+func TestDataFrameDuration(t *testing.T) {
+	type request struct {
+		Path    string
+		Latency time.Duration
+		Timeout *time.Duration
+	}
+	timeout := time.Second
+	df, err := NewFromStructs([]request{{"/a", time.Millisecond, &timeout}, {"/b", time.Minute, nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ := df.GetSeriesByColumn("Latency")
+	if se.Type() != types.TypeDuration {
+		t.Fatalf("struct reader: got type %s", se.Type())
+	}
+	rows := df.ToStructs()
+	if got := rows[0].(*request); got.Latency != time.Millisecond || got.Timeout == nil || *got.Timeout != time.Second {
+		t.Errorf("struct writer: got %+v", got)
+	}
+	if got := rows[1].(*request); got.Timeout != nil {
+		t.Errorf("struct writer: got %v, want nil", *got.Timeout)
+	}
+
+	query, err := df.Query("Latency >= '1s'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.NumRow() != 1 {
+		t.Errorf("query: got %d rows, want 1", query.NumRow())
+	}
+
+	file, err := ioutil.TempFile("", "godas-*.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("Window\nPT15M\n\n1h\n")
+	file.Close()
+	df, err = NewFromCSV(file.Name(), CSVOptions{DurationColumns: []string{"Window"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ = df.GetSeriesByColumn("Window")
+	if got := se.array.Elements.String(); got != "[15m0s 1h0m0s]" {
+		t.Errorf("csv reader: got %s", got)
+	}
+}
+
+func TestSeriesDurationFillNA(t *testing.T) {
+	second := time.Second
+	se, _ := NewSeries([]*time.Duration{nil, &second}, "Latency")
+	filled, err := se.FillNA(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filled.array.Elements.String(); got != "[1m0s 1s]" {
+		t.Errorf("fill na: got %s", got)
+	}
+	if _, err = se.FillNA(60); err == nil {
+		t.Error("expected a fill value type error")
+	}
+}
@@ -0,0 +1,204 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hunknownz/godas/index"
+	sfloat "github.com/hunknownz/godas/internal/elements_float"
+	"github.com/hunknownz/godas/types"
+	"reflect"
+	"time"
+)
+
+const (
+	InterpolateLinear  = "linear"
+	InterpolateNearest = "nearest"
+)
+
+// fillValue converts value to the element type typ, ok reporting whether
+// it can fill a series of that type. Int values fill float series.
+func fillValue(typ types.Type, value interface{}) (fill interface{}, ok bool) {
+	if value == nil {
+		return
+	}
+	kind := reflect.TypeOf(value).Kind()
+	switch typ {
+	case types.TypeInt:
+		switch kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return reflect.ValueOf(value).Int(), true
+		}
+	case types.TypeFloat:
+		switch kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return float64(reflect.ValueOf(value).Int()), true
+		case reflect.Float32, reflect.Float64:
+			return reflect.ValueOf(value).Float(), true
+		}
+	case types.TypeString:
+		return value, kind == reflect.String
+	case types.TypeBool:
+		return value, kind == reflect.Bool
+	case types.TypeDatetime:
+		_, ok = value.(time.Time)
+		return value, ok
+	case types.TypeDuration:
+		_, ok = value.(time.Duration)
+		return value, ok
+	case types.TypeObject:
+		return value, true
+	}
+	return
+}
+
+// subsetSeries returns the elements of se at idx.
+func (se *Series) subsetSeries(idx index.IndexInt) (newSeries *Series, err error) {
+	newElements, err := se.array.Elements.Subset(idx)
+	if err != nil {
+		return
+	}
+	newSeries = se.newDerivedSeries(newElements)
+	return
+}
+
+// FillNA replaces the null and NaN elements with value, which must have the
+// type of the series. Int values can fill float series.
+func (se *Series) FillNA(value interface{}) (newSeries *Series, err error) {
+	fill, ok := fillValue(se.Type(), value)
+	if !ok {
+		err = errors.New(fmt.Sprintf("fill na error: can't fill %s series with %v", se.Type(), value))
+		return
+	}
+	seLen := se.Len()
+	filled, err := se.array.Elements.Append(true, fill)
+	if err != nil {
+		err = fmt.Errorf("fill na error: %w", err)
+		return
+	}
+	idx := make(index.IndexInt, seLen)
+	for i, isNaN := range se.IsNaN() {
+		idx[i] = uint32(i)
+		if isNaN {
+			idx[i] = uint32(seLen)
+		}
+	}
+	newElements, err := filled.Subset(idx)
+	if err != nil {
+		err = fmt.Errorf("fill na error: %w", err)
+		return
+	}
+	newSeries = se.newDerivedSeries(newElements)
+	return
+}
+
+// FFill replaces every null and NaN element with the last element before it
+// that isn't missing. Leading missing elements are kept.
+func (se *Series) FFill() (newSeries *Series, err error) {
+	isNaN := se.IsNaN()
+	idx := make(index.IndexInt, len(isNaN))
+	last := -1
+	for i := range isNaN {
+		idx[i] = uint32(i)
+		if !isNaN[i] {
+			last = i
+		} else if last >= 0 {
+			idx[i] = uint32(last)
+		}
+	}
+	newSeries, err = se.subsetSeries(idx)
+	if err != nil {
+		err = fmt.Errorf("ffill error: %w", err)
+	}
+	return
+}
+
+// BFill replaces every null and NaN element with the first element after it
+// that isn't missing. Trailing missing elements are kept.
+func (se *Series) BFill() (newSeries *Series, err error) {
+	isNaN := se.IsNaN()
+	idx := make(index.IndexInt, len(isNaN))
+	next := -1
+	for i := len(isNaN) - 1; i >= 0; i-- {
+		idx[i] = uint32(i)
+		if !isNaN[i] {
+			next = i
+		} else if next >= 0 {
+			idx[i] = uint32(next)
+		}
+	}
+	newSeries, err = se.subsetSeries(idx)
+	if err != nil {
+		err = fmt.Errorf("bfill error: %w", err)
+	}
+	return
+}
+
+// DropNA returns the series without its null and NaN elements.
+func (se *Series) DropNA() (newSeries *Series, err error) {
+	idx := make(index.IndexInt, 0, se.Len())
+	for i, isNaN := range se.IsNaN() {
+		if !isNaN {
+			idx = append(idx, uint32(i))
+		}
+	}
+	newSeries, err = se.subsetSeries(idx)
+	if err != nil {
+		err = fmt.Errorf("drop na error: %w", err)
+	}
+	return
+}
+
+// Interpolate fills the null and NaN elements of an int or float series
+// lying between two elements that aren't missing, treating positions as
+// evenly spaced. InterpolateLinear returns a float series,
+// InterpolateNearest picks the nearest element, the one before on ties,
+// and keeps the type. Leading and trailing missing elements are kept.
+func (se *Series) Interpolate(method string) (newSeries *Series, err error) {
+	values, isNaN, err := se.floatValues()
+	if err != nil {
+		err = fmt.Errorf("interpolate error: %w", err)
+		return
+	}
+
+	switch method {
+	case InterpolateLinear:
+		result := make([]float64, len(values))
+		copy(result, values)
+		isNull := se.IsNull()
+		prev := -1
+		for i := range values {
+			if isNaN[i] {
+				continue
+			}
+			for j := prev + 1; prev >= 0 && j < i; j++ {
+				ratio := float64(j-prev) / float64(i-prev)
+				result[j], isNull[j] = values[prev]+(values[i]-values[prev])*ratio, false
+			}
+			prev = i
+		}
+		newSeries = se.newDerivedSeries(sfloat.NewNullableElementsFloat64(result, isNull))
+	case InterpolateNearest:
+		idx := make(index.IndexInt, len(values))
+		prev := -1
+		for i := range values {
+			idx[i] = uint32(i)
+			if isNaN[i] {
+				continue
+			}
+			for j := prev + 1; prev >= 0 && j < i; j++ {
+				idx[j] = uint32(prev)
+				if i-j < j-prev {
+					idx[j] = uint32(i)
+				}
+			}
+			prev = i
+		}
+		newSeries, err = se.subsetSeries(idx)
+	default:
+		err = errors.New(fmt.Sprintf("unknown interpolation method %q", method))
+	}
+	if err != nil {
+		err = fmt.Errorf("interpolate error: %w", err)
+	}
+	return
+}
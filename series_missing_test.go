@@ -0,0 +1,119 @@
+package godas
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSeriesFillNA(t *testing.T) {
+	one, four := 1, 4
+	se, _ := NewSeries([]*int{nil, &one, nil, nil, &four, nil}, "value")
+
+	filled, err := se.FillNA(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filled.array.Elements.String(); got != "[0 1 0 0 4 0]" {
+		t.Errorf("fillna: got %s", got)
+	}
+	if _, err = se.FillNA("zero"); err == nil {
+		t.Error("fillna: expected a type error")
+	}
+
+	ffill, _ := se.FFill()
+	if got := ffill.array.Elements.String(); got != "[null 1 1 1 4 4]" {
+		t.Errorf("ffill: got %s", got)
+	}
+	bfill, _ := se.BFill()
+	if got := bfill.array.Elements.String(); got != "[1 1 4 4 4 null]" {
+		t.Errorf("bfill: got %s", got)
+	}
+	dropped, _ := se.DropNA()
+	if got := dropped.array.Elements.String(); got != "[1 4]" {
+		t.Errorf("dropna: got %s", got)
+	}
+
+	linear, err := se.Interpolate(InterpolateLinear)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := linear.array.Elements.String(); got != "[null 1 2 3 4 null]" {
+		t.Errorf("linear: got %s", got)
+	}
+	nearest, _ := se.Interpolate(InterpolateNearest)
+	if got := nearest.array.Elements.String(); got != "[null 1 1 4 4 null]" {
+		t.Errorf("nearest: got %s", got)
+	}
+
+	seFloat, _ := NewSeries([]float64{0, math.NaN(), 1}, "value")
+	linear, _ = seFloat.Interpolate(InterpolateLinear)
+	if got, _ := linear.At(1); got.MustFloat() != 0.5 {
+		t.Errorf("linear nan: got %v, want 0.5", got.MustFloat())
+	}
+	if _, err = seFloat.Interpolate("cubic"); err == nil {
+		t.Error("interpolate: expected an unknown method error")
+	}
+}
+
+func TestDataFrameFillNA(t *testing.T) {
+	type row struct {
+		Name  *string
+		Score *float64
+	}
+	a, score := "a", 2.5
+	df, _ := NewFromStructs([]row{{&a, nil}, {nil, &score}, {nil, nil}})
+
+	dropped, _ := df.DropNA(DropAny)
+	if dropped.NumRow() != 0 {
+		t.Errorf("dropna any: got %d rows, want 0", dropped.NumRow())
+	}
+	dropped, _ = df.DropNA(DropAll)
+	if dropped.NumRow() != 2 {
+		t.Errorf("dropna all: got %d rows, want 2", dropped.NumRow())
+	}
+	dropped, _ = df.DropNA(DropAny, "Score")
+	if dropped.NumRow() != 1 {
+		t.Errorf("dropna subset: got %d rows, want 1", dropped.NumRow())
+	}
+	if _, err := df.DropNA(DropAny, "Missing"); err == nil {
+		t.Error("dropna: expected an unknown column error")
+	}
+
+	filled, err := df.FillNA(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ := filled.GetSeriesByColumn("Score")
+	if got := se.array.Elements.String(); got != "[0 2.5 0]" {
+		t.Errorf("fillna scalar: got %s", got)
+	}
+	se, _ = filled.GetSeriesByColumn("Name")
+	if got := se.array.Elements.String(); got != "[a null null]" {
+		t.Errorf("fillna scalar should skip other types: got %s", got)
+	}
+
+	filled, _ = df.FillNA(map[string]interface{}{"Name": "-"})
+	se, _ = filled.GetSeriesByColumn("Name")
+	if got := se.array.Elements.String(); got != "[a - -]" {
+		t.Errorf("fillna map: got %s", got)
+	}
+	if _, err = df.FillNA(map[string]interface{}{"Name": 1}); err == nil {
+		t.Error("fillna map: expected a type error")
+	}
+
+	filled, _ = df.FFill()
+	se, _ = filled.GetSeriesByColumn("Name")
+	if got := se.array.Elements.String(); got != "[a a a]" {
+		t.Errorf("ffill: got %s", got)
+	}
+	filled, _ = df.BFill()
+	se, _ = filled.GetSeriesByColumn("Score")
+	if got := se.array.Elements.String(); got != "[2.5 2.5 null]" {
+		t.Errorf("bfill: got %s", got)
+	}
+
+	seObject, _ := NewSeries([]interface{}{nil, struct{}{}}, "object")
+	if _, err = seObject.FillNA(1); err != nil {
+		t.Errorf("fillna object: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package godas
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSeriesNull(t *testing.T) {
+	ids, _ := NewSeries([]string{"a", "NaN"}, "ID")
+	stamps, _ := NewSeries([]int64{math.MinInt64, 1}, "Stamp")
+	if ids.IsNaN()[1] || stamps.IsNaN()[0] {
+		t.Error("sentinel values should be valid")
+	}
+
+	one, three := 1.0, 3.0
+	value, err := NewSeries([]*float64{&one, nil, &three}, "Value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, _ = value.Append(true, math.NaN(), nil)
+	if want := []bool{false, true, false, false, true}; !reflect.DeepEqual(value.IsNull(), want) {
+		t.Errorf("null mask: got %v, want %v", value.IsNull(), want)
+	}
+	if want := []bool{false, true, false, true, true}; !reflect.DeepEqual(value.IsNaN(), want) {
+		t.Errorf("nan mask: got %v, want %v", value.IsNaN(), want)
+	}
+	element, _ := value.At(3)
+	if !element.NaN() || element.Null() {
+		t.Error("a float NaN should be missing but not null")
+	}
+
+	subset, _ := value.Subset([]uint32{4, 0, 1})
+	if want := []bool{true, false, true}; !reflect.DeepEqual(subset.IsNull(), want) {
+		t.Errorf("subset null mask: got %v, want %v", subset.IsNull(), want)
+	}
+	sorted, _ := subset.Copy().Sort(false, true)
+	element, _ = sorted.At(2)
+	if element.Null() || element.MustFloat() != 1 || !sorted.IsNull()[0] {
+		t.Errorf("sort should keep the nulls with their elements: got %v", sorted.array.Elements)
+	}
+	if got := subset.array.Elements.String(); got != "[null 1 null]" {
+		t.Errorf("string: got %s", got)
+	}
+
+	two, _ := NewSeries([]int{2, 2, 2, 2, 2}, "Two")
+	product, _ := value.Mul(two)
+	if want := []bool{false, true, false, false, true}; !reflect.DeepEqual(product.IsNull(), want) {
+		t.Errorf("mul null mask: got %v, want %v", product.IsNull(), want)
+	}
+}
+
+func TestDataFrameNull(t *testing.T) {
+	type row struct {
+		Name  string
+		Score *int
+	}
+	score := 7
+	df, err := NewFromStructs([]row{{"a", &score}, {"b", nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ := df.GetSeriesByColumn("Score")
+	if !reflect.DeepEqual(se.IsNull(), []bool{false, true}) {
+		t.Errorf("struct reader: unexpected null mask %v", se.IsNull())
+	}
+	rows := df.ToStructs()
+	if got := rows[0].(*row).Score; got == nil || *got != 7 {
+		t.Errorf("struct writer: got %v, want 7", got)
+	}
+	if got := rows[1].(*row).Score; got != nil {
+		t.Errorf("struct writer: got %v, want nil", *got)
+	}
+
+	file, err := ioutil.TempFile("", "godas-*.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("ID,Country\nNaN,DE\nb,\n")
+	file.Close()
+	df, err = NewFromCSV(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, _ = df.GetSeriesByColumn("Country")
+	if !reflect.DeepEqual(se.IsNull(), []bool{false, true}) {
+		t.Errorf("csv reader: unexpected null mask %v", se.IsNull())
+	}
+	se, _ = df.GetSeriesByColumn("ID")
+	if se.IsNull()[0] {
+		t.Error("csv reader: NaN should be a valid string")
+	}
+}
@@ -1,9 +1,6 @@
 package godas
 
 import (
-	sfloat "github.com/hunknownz/godas/internal/elements_float"
-	sstring "github.com/hunknownz/godas/internal/elements_string"
-	"github.com/hunknownz/godas/types"
 	"sort"
 )
 
@@ -27,25 +24,7 @@ func (sorter *seriesSorter) Swap(i, j int) {
 }
 
 func (sorter *seriesSorter) Sort() {
-	se := sorter.series
 	if sorter.lessFunc == nil {
-		typ := se.Type()
-		switch typ {
-		case types.TypeFloat:
-			elements := se.array.Elements.(sfloat.ElementsFloat64)
-			sortSlice := sort.Float64Slice(elements)
-			if !sorter.ascending {
-				sort.Sort(sort.Reverse(sortSlice))
-			}
-			sort.Sort(sortSlice)
-		case types.TypeString:
-			elements := se.array.Elements.(sstring.ElementsString)
-			sortSlice := sort.StringSlice(elements)
-			if !sorter.ascending {
-				sort.Sort(sort.Reverse(sortSlice))
-			}
-			sort.Sort(sortSlice)
-		}
 		return
 	}
 
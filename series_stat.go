@@ -0,0 +1,431 @@
+package godas
+
+import (
+	"errors"
+	"fmt"
+	sduration "github.com/hunknownz/godas/internal/elements_duration"
+	sfloat "github.com/hunknownz/godas/internal/elements_float"
+	sint "github.com/hunknownz/godas/internal/elements_int"
+	"math"
+	"sort"
+)
+
+func checkSkipNaN(skipNaN []bool) bool {
+	if len(skipNaN) > 0 {
+		return skipNaN[0]
+	}
+	return true
+}
+
+// numericValues reads the int or float elements of the series as float64,
+// and the durations as nanoseconds.
+// NaN elements are dropped when skipNaN is set, otherwise hasNaN reports
+// whether any were found.
+func (se *Series) numericValues(skipNaN bool) (values []float64, hasNaN bool, err error) {
+	isNaN := se.IsNaN()
+	switch se.array.Elements.(type) {
+	case sint.ElementsInt64:
+		elements := se.array.Elements.(sint.ElementsInt64).Values()
+		values = make([]float64, 0, len(elements))
+		for i, element := range elements {
+			if isNaN[i] {
+				hasNaN = true
+				continue
+			}
+			values = append(values, float64(element))
+		}
+	case sduration.ElementsDuration:
+		elements := se.array.Elements.(sduration.ElementsDuration).Values()
+		values = make([]float64, 0, len(elements))
+		for i, element := range elements {
+			if isNaN[i] {
+				hasNaN = true
+				continue
+			}
+			values = append(values, float64(element))
+		}
+	case sfloat.ElementsFloat64:
+		elements := se.array.Elements.(sfloat.ElementsFloat64).Values()
+		values = make([]float64, 0, len(elements))
+		for i, element := range elements {
+			if isNaN[i] {
+				hasNaN = true
+				continue
+			}
+			values = append(values, element)
+		}
+	default:
+		err = errors.New(fmt.Sprintf("type %s is not numeric", se.Type()))
+		return
+	}
+	if skipNaN {
+		hasNaN = false
+	}
+	return
+}
+
+func (se *Series) reduce(name string, skipNaN []bool, f func(values []float64) float64) (result float64, err error) {
+	values, hasNaN, err := se.numericValues(checkSkipNaN(skipNaN))
+	if err != nil {
+		err = fmt.Errorf("%s error: %w", name, err)
+		return
+	}
+	if hasNaN {
+		result = math.NaN()
+		return
+	}
+	result = f(values)
+	return
+}
+
+// Count returns the number of non-NaN elements.
+func (se *Series) Count() int {
+	count := 0
+	for _, isNaN := range se.IsNaN() {
+		if !isNaN {
+			count++
+		}
+	}
+	return count
+}
+
+// Sum returns the sum of the elements. NaN elements are skipped unless
+// skipNaN is false, in which case any NaN makes the result NaN. The same
+// holds for the other reductions.
+func (se *Series) Sum(skipNaN ...bool) (float64, error) {
+	return se.reduce("sum", skipNaN, sum)
+}
+
+func (se *Series) Mean(skipNaN ...bool) (float64, error) {
+	return se.reduce("mean", skipNaN, mean)
+}
+
+func (se *Series) Median(skipNaN ...bool) (float64, error) {
+	return se.reduce("median", skipNaN, func(values []float64) float64 {
+		return quantile(values, 0.5)
+	})
+}
+
+func (se *Series) Min(skipNaN ...bool) (float64, error) {
+	return se.reduce("min", skipNaN, func(values []float64) float64 {
+		if len(values) == 0 {
+			return math.NaN()
+		}
+		result := values[0]
+		for _, value := range values[1:] {
+			result = math.Min(result, value)
+		}
+		return result
+	})
+}
+
+func (se *Series) Max(skipNaN ...bool) (float64, error) {
+	return se.reduce("max", skipNaN, func(values []float64) float64 {
+		if len(values) == 0 {
+			return math.NaN()
+		}
+		result := values[0]
+		for _, value := range values[1:] {
+			result = math.Max(result, value)
+		}
+		return result
+	})
+}
+
+// Var returns the sample variance of the elements.
+func (se *Series) Var(skipNaN ...bool) (float64, error) {
+	return se.reduce("var", skipNaN, variance)
+}
+
+// Std returns the sample standard deviation of the elements.
+func (se *Series) Std(skipNaN ...bool) (float64, error) {
+	return se.reduce("std", skipNaN, func(values []float64) float64 {
+		return math.Sqrt(variance(values))
+	})
+}
+
+// Quantile returns the q-th quantile of the elements, interpolating
+// linearly between the closest ranks. q must be in [0, 1].
+func (se *Series) Quantile(q float64, skipNaN ...bool) (result float64, err error) {
+	if q < 0 || q > 1 {
+		err = errors.New(fmt.Sprintf("quantile error: q %v must be in [0, 1]", q))
+		return
+	}
+	return se.reduce("quantile", skipNaN, func(values []float64) float64 {
+		return quantile(values, q)
+	})
+}
+
+// Skew returns the adjusted Fisher-Pearson sample skewness.
+func (se *Series) Skew(skipNaN ...bool) (float64, error) {
+	return se.reduce("skew", skipNaN, skew)
+}
+
+// Kurtosis returns the bias corrected sample excess kurtosis.
+func (se *Series) Kurtosis(skipNaN ...bool) (float64, error) {
+	return se.reduce("kurtosis", skipNaN, kurtosis)
+}
+
+func sum(values []float64) float64 {
+	result := float64(0)
+	for _, value := range values {
+		result += value
+	}
+	return result
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	return sum(values) / float64(len(values))
+}
+
+// centralMoments returns the biased second, third and fourth central moments.
+func centralMoments(values []float64) (m2, m3, m4 float64) {
+	n := float64(len(values))
+	avg := mean(values)
+	for _, value := range values {
+		d := value - avg
+		d2 := d * d
+		m2 += d2
+		m3 += d2 * d
+		m4 += d2 * d2
+	}
+	return m2 / n, m3 / n, m4 / n
+}
+
+func variance(values []float64) float64 {
+	n := len(values)
+	if n < 2 {
+		return math.NaN()
+	}
+	m2, _, _ := centralMoments(values)
+	return m2 * float64(n) / float64(n-1)
+}
+
+func skew(values []float64) float64 {
+	n := float64(len(values))
+	if n < 3 {
+		return math.NaN()
+	}
+	m2, m3, _ := centralMoments(values)
+	if m2 == 0 {
+		return 0
+	}
+	g1 := m3 / math.Pow(m2, 1.5)
+	return math.Sqrt(n*(n-1)) / (n - 2) * g1
+}
+
+func kurtosis(values []float64) float64 {
+	n := float64(len(values))
+	if n < 4 {
+		return math.NaN()
+	}
+	m2, _, m4 := centralMoments(values)
+	if m2 == 0 {
+		return 0
+	}
+	g2 := m4/(m2*m2) - 3
+	return (n - 1) / ((n - 2) * (n - 3)) * ((n+1)*g2 + 6)
+}
+
+func quantile(values []float64, q float64) float64 {
+	n := len(values)
+	if n == 0 {
+		return math.NaN()
+	}
+	sorted := make([]float64, n)
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	pos := q * float64(n-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+const (
+	CorrPearson  = "pearson"
+	CorrSpearman = "spearman"
+	CorrKendall  = "kendall"
+)
+
+// floatValues reads the int or float elements of the series as float64
+// together with their NaN mask, keeping every position.
+func (se *Series) floatValues() (values []float64, isNaN []bool, err error) {
+	isNaN = se.IsNaN()
+	switch se.array.Elements.(type) {
+	case sint.ElementsInt64:
+		elements := se.array.Elements.(sint.ElementsInt64).Values()
+		values = make([]float64, len(elements))
+		for i, element := range elements {
+			values[i] = float64(element)
+		}
+	case sfloat.ElementsFloat64:
+		values = se.array.Elements.(sfloat.ElementsFloat64).Values()
+	default:
+		err = errors.New(fmt.Sprintf("type %s is not numeric", se.Type()))
+	}
+	return
+}
+
+// pairwiseComplete keeps the positions where neither x nor y is NaN.
+func pairwiseComplete(x, y []float64, xNaN, yNaN []bool) (xs, ys []float64) {
+	xs = make([]float64, 0, len(x))
+	ys = make([]float64, 0, len(y))
+	for i := range x {
+		if xNaN[i] || yNaN[i] {
+			continue
+		}
+		xs = append(xs, x[i])
+		ys = append(ys, y[i])
+	}
+	return
+}
+
+// Corr returns the correlation with another series of the same length,
+// using the pairwise complete observations. The method defaults to
+// CorrPearson.
+func (se *Series) Corr(other *Series, method ...string) (result float64, err error) {
+	if se.Len() != other.Len() {
+		err = errors.New(fmt.Sprintf("corr error: series length %d doesn't match %d", se.Len(), other.Len()))
+		return
+	}
+	corrMethod := CorrPearson
+	if len(method) > 0 {
+		corrMethod = method[0]
+	}
+	x, xNaN, err := se.floatValues()
+	if err != nil {
+		err = fmt.Errorf("corr error: %w", err)
+		return
+	}
+	y, yNaN, err := other.floatValues()
+	if err != nil {
+		err = fmt.Errorf("corr error: %w", err)
+		return
+	}
+	xs, ys := pairwiseComplete(x, y, xNaN, yNaN)
+	result, err = correlation(corrMethod, xs, ys)
+	if err != nil {
+		err = fmt.Errorf("corr error: %w", err)
+	}
+	return
+}
+
+// Cov returns the sample covariance with another series of the same length,
+// using the pairwise complete observations.
+func (se *Series) Cov(other *Series) (result float64, err error) {
+	if se.Len() != other.Len() {
+		err = errors.New(fmt.Sprintf("cov error: series length %d doesn't match %d", se.Len(), other.Len()))
+		return
+	}
+	x, xNaN, err := se.floatValues()
+	if err != nil {
+		err = fmt.Errorf("cov error: %w", err)
+		return
+	}
+	y, yNaN, err := other.floatValues()
+	if err != nil {
+		err = fmt.Errorf("cov error: %w", err)
+		return
+	}
+	result = covariance(pairwiseComplete(x, y, xNaN, yNaN))
+	return
+}
+
+func correlation(method string, xs, ys []float64) (result float64, err error) {
+	switch method {
+	case CorrPearson:
+		result = pearson(xs, ys)
+	case CorrSpearman:
+		result = pearson(rank(xs), rank(ys))
+	case CorrKendall:
+		result = kendall(xs, ys)
+	default:
+		err = errors.New(fmt.Sprintf("unknown correlation method %q", method))
+	}
+	return
+}
+
+func covariance(xs, ys []float64) float64 {
+	n := len(xs)
+	if n < 2 {
+		return math.NaN()
+	}
+	xMean, yMean := mean(xs), mean(ys)
+	result := float64(0)
+	for i := 0; i < n; i++ {
+		result += (xs[i] - xMean) * (ys[i] - yMean)
+	}
+	return result / float64(n-1)
+}
+
+func pearson(xs, ys []float64) float64 {
+	if len(xs) < 2 {
+		return math.NaN()
+	}
+	xMean, yMean := mean(xs), mean(ys)
+	var sxy, sxx, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-xMean, ys[i]-yMean
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// rank returns the 1-based ranks of values, giving tied values the average
+// of the ranks they span.
+func rank(values []float64) []float64 {
+	n := len(values)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return values[order[i]] < values[order[j]]
+	})
+
+	ranks := make([]float64, n)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && values[order[j]] == values[order[i]] {
+			j++
+		}
+		avgRank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			ranks[order[k]] = avgRank
+		}
+		i = j
+	}
+	return ranks
+}
+
+// kendall returns the tau-b rank correlation, which accounts for ties.
+func kendall(xs, ys []float64) float64 {
+	n := len(xs)
+	if n < 2 {
+		return math.NaN()
+	}
+	var concordant, discordant, xTies, yTies float64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			dx, dy := xs[i]-xs[j], ys[i]-ys[j]
+			switch {
+			case dx == 0 && dy == 0:
+			case dx == 0:
+				xTies++
+			case dy == 0:
+				yTies++
+			case (dx > 0) == (dy > 0):
+				concordant++
+			default:
+				discordant++
+			}
+		}
+	}
+	return (concordant - discordant) / math.Sqrt((concordant+discordant+xTies)*(concordant+discordant+yTies))
+}
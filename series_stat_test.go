@@ -0,0 +1,126 @@
+package godas
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestSeriesStatistics(t *testing.T) {
+	se, _ := NewSeries([]float64{2, 4, 4, 4, 5, 5, 7, 9, math.NaN()}, "value")
+
+	cases := []struct {
+		name string
+		f    func(...bool) (float64, error)
+		want float64
+	}{
+		{"sum", se.Sum, 40},
+		{"mean", se.Mean, 5},
+		{"median", se.Median, 4.5},
+		{"min", se.Min, 2},
+		{"max", se.Max, 9},
+		{"var", se.Var, 32.0 / 7},
+		{"skew", se.Skew, 0.8184875533567997},
+		{"kurtosis", se.Kurtosis, 0.940625},
+	}
+	for _, c := range cases {
+		got, err := c.f()
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if !almostEqual(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+		got, _ = c.f(false)
+		if !math.IsNaN(got) {
+			t.Errorf("%s without skipping nan: got %v, want NaN", c.name, got)
+		}
+	}
+
+	if se.Count() != 8 {
+		t.Errorf("count: got %d, want 8", se.Count())
+	}
+	q, _ := se.Quantile(0.25)
+	if !almostEqual(q, 4) {
+		t.Errorf("quantile: got %v, want 4", q)
+	}
+	if _, err := se.Quantile(2); err == nil {
+		t.Error("quantile out of range should fail")
+	}
+}
+
+func TestDataFrameDescribe(t *testing.T) {
+	age, _ := NewSeries([]int{10, 20, 30}, "Age")
+	name, _ := NewSeries([]string{"a", "b", "a"}, "Name")
+	df, _ := NewFromSeries(age, name)
+
+	desc, err := df.Describe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.NumRow() != len(describeMixedStats) || desc.NumColumn() != 3 {
+		t.Fatalf("unexpected shape %dx%d", desc.NumRow(), desc.NumColumn())
+	}
+	mean, _ := desc.At(4, "Age")
+	if mean.MustFloat() != 20 {
+		t.Errorf("mean: got %v, want 20", mean.MustFloat())
+	}
+	top, _ := desc.At(2, "Name")
+	if top.MustInterface() != "a" {
+		t.Errorf("top: got %v, want a", top.MustInterface())
+	}
+}
+
+func TestSeriesCorr(t *testing.T) {
+	x, _ := NewSeries([]float64{1, 2, 3, 4, math.NaN()}, "X")
+	y, _ := NewSeries([]int{2, 4, 5, 9, 1}, "Y")
+
+	cases := []struct {
+		method string
+		want   float64
+	}{
+		{CorrPearson, 11 / math.Sqrt(130)},
+		{CorrSpearman, 1},
+		{CorrKendall, 1},
+	}
+	for _, c := range cases {
+		got, err := x.Corr(y, c.method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !almostEqual(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.method, got, c.want)
+		}
+	}
+	if _, err := x.Corr(y, "unknown"); err == nil {
+		t.Error("unknown method should fail")
+	}
+}
+
+func TestDataFrameCorrCov(t *testing.T) {
+	x, _ := NewSeries([]float64{1, 2, 3, 4}, "X")
+	y, _ := NewSeries([]int{4, 3, 2, 1}, "Y")
+	name, _ := NewSeries([]string{"a", "b", "c", "d"}, "Name")
+	df, _ := NewFromSeries(x, name, y)
+
+	corr, err := df.Corr(CorrPearson)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corr.NumRow() != 2 || corr.NumColumn() != 3 {
+		t.Fatalf("unexpected shape %dx%d", corr.NumRow(), corr.NumColumn())
+	}
+	value, _ := corr.At(0, "Y")
+	if !almostEqual(value.MustFloat(), -1) {
+		t.Errorf("corr X-Y: got %v, want -1", value.MustFloat())
+	}
+
+	cov, _ := df.Cov()
+	value, _ = cov.At(0, "X")
+	if !almostEqual(value.MustFloat(), 5.0/3) {
+		t.Errorf("cov X-X: got %v, want %v", value.MustFloat(), 5.0/3)
+	}
+}
@@ -1,71 +1,76 @@
-package series_test
+package godas
 
 import (
-	"fmt"
-	"github.com/hunknownz/godas/order"
+	"reflect"
 	"testing"
-	"github.com/hunknownz/godas/series"
 )
 
 func TestNewSeries(t *testing.T) {
-	dataInt := []int{
-		1,2,3,4,5,
-	}
-	seriesInt, _ := series.New(dataInt, "test")
+	seriesInt, _ := NewSeries([]int{1, 2, 3, 4, 5}, "test")
 	valInt, _ := seriesInt.At(2)
-	fmt.Printf("%v\n", valInt.MustInt())
+	if valInt.MustInt() != 3 {
+		t.Errorf("int: got %v", valInt.MustInt())
+	}
 
-	dataBool := []bool{
-		true,false,true,false,true,false,true,false,true,false,true,false,true,false,true,false,
-		true,false,true,false,true,false,true,false,true,false,true,false,true,false,true,false,
-		true,false,true,false,true,false,true,false,true,false,true,false,true,false,true,false,
-		true,false,true,false,true,false,true,false,true,false,true,false,true,false,true,false,
-		true,false,true,false,true,false,true,false,true,false,true,false,true,false,true,false,
+	dataBool := make([]bool, 128)
+	for i := range dataBool {
+		dataBool[i] = i%2 == 0
+	}
+	seriesBool, _ := NewSeries(dataBool, "test")
+	if seriesBool.Len() != 128 {
+		t.Errorf("bool: got %d elements, want 128", seriesBool.Len())
+	}
+	for _, i := range []int{2, 65, 127} {
+		value, _ := seriesBool.At(i)
+		if value.MustBool() != dataBool[i] {
+			t.Errorf("bool at %d: got %v", i, value.MustBool())
+		}
 	}
-	seriesBool, _ := series.New(dataBool, "test")
-	boolValue, _ := seriesBool.At(2)
-	fmt.Printf("%v\n", boolValue.MustBool())
 
-	dataString := []string{
-		"test1", "test2", "NaN",
+	seriesString, _ := NewSeries([]string{"test1", "test2"}, "text")
+	valString, _ := seriesString.At(1)
+	if valString.MustString() != "test2" {
+		t.Errorf("string: got %v", valString.MustString())
 	}
-	seriesString, _ := series.New(dataString, "text")
-	fmt.Printf("%v\n", seriesString.IsNaN())
 }
 
 func TestSeriesCondition(t *testing.T) {
-	dataInt := []int{
-		1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,
-	}
-	seriesInt, _ := series.New(dataInt, "test")
-
-	condition := series.NewCondition()
-	condition.Or("<", 5)
-	condition.And(">", 3)
-	condition.Or(">", 7)
-	condition.And("<", 9)
-	condition.Or(">", 13)
-
-
-	fmt.Printf("%v\n", condition)
+	seriesInt, _ := NewSeries([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, "test")
+	cond := NewSeriesCondition()
+	cond.Or("<", 5)
+	cond.And(">", 3)
+	cond.Or(">", 7)
+	cond.And("<", 9)
+	cond.Or(">", 13)
 
-	ixs, _ :=seriesInt.IsCondition(condition)
-	fmt.Printf("%v\n", ixs)
+	ixs, err := seriesInt.IsCondition(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []int
+	for i, ok := range ixs {
+		if ok {
+			got = append(got, i+1)
+		}
+	}
+	if !reflect.DeepEqual(got, []int{4, 8, 14, 15}) {
+		t.Errorf("got %v", got)
+	}
 }
 
 func TestSeriesSort(t *testing.T) {
-	dataInt := []int{
-		5,4,1,3,8,6,9,2,1,5,
-	}
-	seriesInt, _ := series.New(dataInt, "test")
-
+	seriesInt, _ := NewSeries([]int{5, 4, 1, 3, 8, 6, 9, 2, 1, 5}, "test")
 	f := func(a, b int64) bool {
 		return a < b
 	}
-	lessFunc := seriesInt.NewIntLessFunc(order.IntLessFunc(f))
+	lessFunc := seriesInt.NewIntLessFunc(IntLessFunc(f))
 	seriesInt.Sort(true, true, lessFunc)
-	for i:=0; i<seriesInt.Len(); i++ {
-		val, _ := seriesInt.At(i)
-		fmt.Printf("%v\n", val.MustInt())
+	var got []int64
+	for i := 0; i < seriesInt.Len(); i++ {
+		value, _ := seriesInt.At(i)
+		got = append(got, value.MustInt())
+	}
+	if !reflect.DeepEqual(got, []int64{1, 1, 2, 3, 4, 5, 5, 6, 8, 9}) {
+		t.Errorf("got %v", got)
 	}
-}
\ No newline at end of file
+}
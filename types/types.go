@@ -8,5 +8,7 @@ const (
 	TypeBool Type = "bool"
 	TypeFloat Type = "float"
 	TypeObject Type = "object"
+	TypeDatetime Type = "datetime"
+	TypeDuration Type = "duration"
 	TypeComposite Type = "composite"
 )
\ No newline at end of file